@@ -657,6 +657,33 @@ func (a *Api) StopPlan(ctx context.Context, planId, branch string) *shared.ApiEr
 	return nil
 }
 
+func (a *Api) DetachPlan(ctx context.Context, planId, branch string) *shared.ApiError {
+	serverUrl := fmt.Sprintf("%s/plans/%s/%s/detach", GetApiHost(), planId, branch)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, serverUrl, nil)
+	if err != nil {
+		return &shared.ApiError{Msg: fmt.Sprintf("error creating request: %v", err)}
+	}
+
+	resp, err := authenticatedFastClient.Do(req)
+	if err != nil {
+		return &shared.ApiError{Msg: fmt.Sprintf("error sending request: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		errorBody, _ := io.ReadAll(resp.Body)
+		apiErr := HandleApiError(resp, errorBody)
+		didRefresh, apiErr := refreshAuthIfNeeded(apiErr)
+		if didRefresh {
+			return a.DetachPlan(ctx, planId, branch)
+		}
+		return apiErr
+	}
+
+	return nil
+}
+
 func (a *Api) GetCurrentPlanState(planId, branch string) (*shared.CurrentPlanState, *shared.ApiError) {
 	return a.getCurrentPlanState(planId, branch, "")
 }
@@ -2383,7 +2410,7 @@ func (a *Api) GetContextBody(planId, branch, contextId string) (*shared.GetConte
 	return &respBody, nil
 }
 
-func (a *Api) AutoLoadContext(ctx context.Context, planId, branch string, req shared.LoadContextRequest) (*shared.LoadContextResponse, *shared.ApiError) {
+func (a *Api) AutoLoadContext(ctx context.Context, planId, branch, loadContextId string, req shared.LoadContextRequest) (*shared.LoadContextResponse, *shared.ApiError) {
 	serverUrl := fmt.Sprintf("%s/plans/%s/%s/auto_load_context", GetApiHost(), planId, branch)
 	reqBytes, err := json.Marshal(req)
 	if err != nil {
@@ -2398,6 +2425,8 @@ func (a *Api) AutoLoadContext(ctx context.Context, planId, branch string, req sh
 
 	// Set the content type header
 	httpReq.Header.Set("Content-Type", "application/json")
+	// lets the server match the response to the load context message it's waiting on
+	httpReq.Header.Set(shared.LoadContextIdHeader, loadContextId)
 
 	// Use the slow client since we may be uploading relatively large files
 	resp, err := authenticatedSlowClient.Do(httpReq)
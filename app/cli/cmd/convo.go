@@ -150,6 +150,10 @@ func convo(cmd *cobra.Command, args []string) {
 			}
 		}
 
+		if msg.ModelFallback != nil {
+			convo += fmt.Sprintf(" ↪️  Fell back from %s to %s (%s)\n\n", msg.ModelFallback.FromModel, msg.ModelFallback.ToModel, msg.ModelFallback.Reason)
+		}
+
 		totalTokens += msg.Tokens
 	}
 
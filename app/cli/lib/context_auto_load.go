@@ -14,7 +14,7 @@ import (
 	"github.com/sashabaranov/go-openai"
 )
 
-func AutoLoadContextFiles(ctx context.Context, files []string) (string, error) {
+func AutoLoadContextFiles(ctx context.Context, files []string, loadContextId string) (string, error) {
 	contexts, err := api.Client.ListContext(CurrentPlanId, CurrentBranch)
 	if err != nil {
 		return "", fmt.Errorf("failed to get contexts: %v", err)
@@ -131,7 +131,7 @@ func AutoLoadContextFiles(ctx context.Context, files []string) (string, error) {
 	}
 
 	// even if there are no files to load, we still need to hit the API endpoint because the stream is waiting on a channel for the autoload to finish
-	res, apiErr := api.Client.AutoLoadContext(ctx, CurrentPlanId, CurrentBranch, loadContextReqs)
+	res, apiErr := api.Client.AutoLoadContext(ctx, CurrentPlanId, CurrentBranch, loadContextId, loadContextReqs)
 	if apiErr != nil {
 		return "", fmt.Errorf("failed to load context: %v", apiErr.Msg)
 	}
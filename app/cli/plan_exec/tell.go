@@ -134,6 +134,7 @@ func TellPlan(
 		apiErr := api.Client.TellPlan(params.CurrentPlanId, params.CurrentBranch, shared.TellPlanRequest{
 			Prompt:                 prompt,
 			ConnectStream:          !tellBg,
+			StopOnDisconnect:       !tellBg,
 			AutoContinue:           !tellStop,
 			ProjectPaths:           paths.ActivePaths,
 			BuildMode:              buildMode,
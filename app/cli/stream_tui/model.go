@@ -72,6 +72,9 @@ type streamUIModel struct {
 
 	prompt string
 
+	tokenUsage    *shared.TokenUsage
+	modelFallback *shared.ModelFallback
+
 	stopped    bool
 	background bool
 	finished   bool
@@ -82,6 +85,8 @@ type streamUIModel struct {
 	updateDebouncer *UpdateDebouncer
 
 	autoLoadContextCancelFn context.CancelFunc
+	// id of the last load context message handled -- the server re-sends it if the response is slow
+	loadContextId string
 
 	buildViewCollapsed bool
 	userToggledBuild   bool
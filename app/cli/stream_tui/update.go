@@ -106,6 +106,16 @@ func (m streamUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case bubbleKey.Matches(msg, m.keymap.background):
 			state := m.readState()
 			if state.canSendToBg {
+				// let the server know the plan was sent to the background so it keeps running after the stream disconnects
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+				apiErr := api.Client.DetachPlan(ctx, lib.CurrentPlanId, lib.CurrentBranch)
+				if apiErr != nil {
+					log.Println("detach plan api error:", apiErr)
+					m.updateState(func() {
+						m.apiErr = apiErr
+					})
+				}
 				m.updateState(func() {
 					m.background = true
 				})
@@ -497,11 +507,17 @@ func (m *streamUIModel) streamUpdate(msg *shared.StreamMessage, deferUIUpdate bo
 
 		// Instead of blocking here, we'll spawn a command
 	case shared.StreamMessageLoadContext:
+		if msg.LoadContextId != "" && msg.LoadContextId == m.readState().loadContextId {
+			log.Println("Already loading context for this message, ignoring re-send")
+			return m, nil
+		}
+
 		m.updateState(func() {
 			m.processing = true
+			m.loadContextId = msg.LoadContextId
 		})
 		return m, tea.Batch(
-			loadContextCmd(msg.LoadContextFiles),
+			loadContextCmd(msg.LoadContextFiles, msg.LoadContextId),
 			tea.Tick(time.Second/10, func(t time.Time) tea.Msg {
 				return spinner.TickMsg{}
 			}),
@@ -532,6 +548,22 @@ func (m *streamUIModel) streamUpdate(msg *shared.StreamMessage, deferUIUpdate bo
 		})
 		return m, tea.Quit
 
+	case shared.StreamMessageTokenUsage:
+		m.updateState(func() {
+			m.tokenUsage = msg.TokenUsage
+		})
+
+	case shared.StreamMessageModelFallback:
+		// a nil fallback means the plan is back on the configured model
+		if msg.ModelFallback == nil {
+			log.Println("Model fallback cleared")
+		} else {
+			log.Printf("Model fallback (%s): %s -> %s\n", msg.ModelFallback.Reason, msg.ModelFallback.FromModel, msg.ModelFallback.ToModel)
+		}
+		m.updateState(func() {
+			m.modelFallback = msg.ModelFallback
+		})
+
 	case shared.StreamMessageRepliesFinished:
 		log.Println("Replies finished, setting processing to false")
 		state := m.readState()
@@ -746,13 +778,13 @@ type contextLoadDoneMsg struct {
 	err  error
 }
 
-func loadContextCmd(loadContextFiles []string) tea.Cmd {
+func loadContextCmd(loadContextFiles []string, loadContextId string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
 		// Run the long operation directly
-		text, err := lib.AutoLoadContextFiles(ctx, loadContextFiles)
+		text, err := lib.AutoLoadContextFiles(ctx, loadContextFiles, loadContextId)
 
 		// Return the result as a message
 		return contextLoadDoneMsg{
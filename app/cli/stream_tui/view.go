@@ -54,6 +54,12 @@ func (m streamUIModel) renderHelp() string {
 			s += " • (b)ackground"
 		}
 		s += " • (j/k) scroll • (d/u) page • (g/G) start/end"
+		if m.modelFallback != nil {
+			s += fmt.Sprintf(" • fell back to %s (%s)", m.modelFallback.ToModel, m.modelFallback.Reason)
+		}
+		if m.tokenUsage != nil {
+			s += fmt.Sprintf(" • %d 🪙 in | %d 🪙 out", m.tokenUsage.PromptTokens, m.tokenUsage.CompletionTokens)
+		}
 		return style.Render(s)
 	}
 }
@@ -212,7 +212,12 @@ func HandleApiError(apiError *shared.ApiError) {
 	}
 
 	StopSpinner()
-	OutputErrorAndExit(apiError.Msg)
+
+	msg := apiError.Msg
+	if apiError.TraceId != "" {
+		msg += fmt.Sprintf("\n\nTrace ID: %s (include this if you report the issue)", apiError.TraceId)
+	}
+	OutputErrorAndExit(msg)
 }
 
 func maybeJSON(s string) bool {
@@ -67,6 +67,7 @@ type ApiClient interface {
 	DeleteAllPlans(projectId string) *shared.ApiError
 	ConnectPlan(planId, branch string, onStreamPlan OnStreamPlan) *shared.ApiError
 	StopPlan(ctx context.Context, planId, branch string) *shared.ApiError
+	DetachPlan(ctx context.Context, planId, branch string) *shared.ApiError
 
 	ArchivePlan(planId string) *shared.ApiError
 	UnarchivePlan(planId string) *shared.ApiError
@@ -119,6 +120,6 @@ type ApiClient interface {
 
 	GetFileMap(req shared.GetFileMapRequest) (*shared.GetFileMapResponse, *shared.ApiError)
 	GetContextBody(planId, branch, contextId string) (*shared.GetContextBodyResponse, *shared.ApiError)
-	AutoLoadContext(ctx context.Context, planId, branch string, req shared.LoadContextRequest) (*shared.LoadContextResponse, *shared.ApiError)
+	AutoLoadContext(ctx context.Context, planId, branch, loadContextId string, req shared.LoadContextRequest) (*shared.LoadContextResponse, *shared.ApiError)
 	GetBuildStatus(planId, branch string) (*shared.GetBuildStatusResponse, *shared.ApiError)
 }
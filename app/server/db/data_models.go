@@ -652,6 +652,7 @@ type ConvoMessage struct {
 	Flags                 shared.ConvoMessageFlags `json:"flags"`
 	ActivatedPaths        map[string]bool          `json:"activatePaths,omitempty"`
 	ActivatedPathsOrdered []string                 `json:"activatePathsOrdered,omitempty"`
+	ModelFallback         *shared.ModelFallback    `json:"modelFallback,omitempty"`
 	CreatedAt             time.Time                `json:"createdAt"`
 }
 
@@ -672,6 +673,7 @@ func (msg *ConvoMessage) ToApi() *shared.ConvoMessage {
 		Subtask:         msg.Subtask.ToApi(),
 		AddedSubtasks:   addedSubtasks,
 		RemovedSubtasks: msg.RemovedSubtasks,
+		ModelFallback:   msg.ModelFallback,
 		CreatedAt:       msg.CreatedAt,
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"plandex-server/db"
+	modelPlan "plandex-server/model/plan"
 
 	shared "plandex-shared"
 
@@ -427,6 +428,9 @@ func DeleteContextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// if a plan is streaming, drop the removed contexts from its next iteration and let it auto load their paths again
+	modelPlan.RemoveActivePlanContexts(planId, branchName, toRemove)
+
 	res := shared.DeleteContextResponse{
 		TokensRemoved: removeTokens,
 		TotalTokens:   branch.ContextTokens - removeTokens,
@@ -12,6 +12,7 @@ import (
 	"plandex-server/host"
 	modelPlan "plandex-server/model/plan"
 	"plandex-server/notify"
+	"plandex-server/shutdown"
 	"plandex-server/types"
 	"time"
 
@@ -113,6 +114,10 @@ func TellPlanHandler(w http.ResponseWriter, r *http.Request) {
 
 	if requestBody.ConnectStream {
 		startResponseStream(r.Context(), w, auth, planId, branch, false)
+
+		if requestBody.StopOnDisconnect {
+			go stopPlanIfAbandoned(plan, branch, auth)
+		}
 	}
 
 	log.Println("Successfully processed request for TellPlanHandler")
@@ -281,7 +286,8 @@ func StopPlanHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if authorizePlan(w, planId, auth) == nil {
+	plan := authorizePlan(w, planId, auth)
+	if plan == nil {
 		return
 	}
 
@@ -295,21 +301,72 @@ func StopPlanHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("Sleeping for 100ms before canceling")
 	time.Sleep(100 * time.Millisecond)
 
-	var err error
-	ctx, cancel := context.WithCancel(r.Context())
+	err := stopPlan(r.Context(), plan, branch, auth)
+
+	if err != nil {
+		log.Printf("Error storing partial reply: %v\n", err)
+		http.Error(w, "Error storing partial reply", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("Successfully processed request for StopPlanHandler")
+}
+
+func DetachPlanHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for DetachPlanHandler", "ip:", host.Ip)
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+	log.Println("planId: ", planId)
+	log.Println("branch: ", branch)
+	active := modelPlan.GetActivePlan(planId, branch)
+	isProxy := r.URL.Query().Get("proxy") == "true"
+
+	if active == nil {
+		if isProxy {
+			log.Println("No active plan on proxied request")
+			http.Error(w, "No active plan", http.StatusNotFound)
+			return
+		}
+		proxyActivePlanMethod(w, r, planId, branch, "detach")
+		return
+	}
+
+	auth := Authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	if authorizePlan(w, planId, auth) == nil {
+		return
+	}
+
+	err := modelPlan.Detach(planId, branch)
+
+	if err != nil {
+		log.Printf("Error detaching plan: %v\n", err)
+		http.Error(w, "Error detaching plan", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("Successfully processed request for DetachPlanHandler")
+}
+
+// stopPlan stores the active plan's partial reply, then stops it -- the plan is stopped even if storing the reply fails
+func stopPlan(ctx context.Context, plan *db.Plan, branch string, auth *types.ServerAuth) error {
+	planId := plan.Id
+	ctx, cancel := context.WithCancel(ctx)
 
-	// this is here to ensure that the plan is stopped even if the db operation fails
 	defer func() {
-		err = modelPlan.Stop(planId, branch, auth.User.Id, auth.OrgId)
+		err := modelPlan.Stop(planId, branch, auth.User.Id, auth.OrgId)
 
 		if err != nil {
 			log.Printf("Error stopping plan: %v\n", err)
 		}
-
-		log.Println("Successfully processed request for StopPlanHandler")
 	}()
 
-	err = db.ExecRepoOperation(db.ExecRepoOperationParams{
+	return db.ExecRepoOperation(db.ExecRepoOperationParams{
 		OrgId:    auth.OrgId,
 		UserId:   auth.User.Id,
 		PlanId:   planId,
@@ -320,15 +377,27 @@ func StopPlanHandler(w http.ResponseWriter, r *http.Request) {
 		CancelFn: cancel,
 	}, func(repo *db.GitRepo) error {
 		log.Println("Stopping plan - storing partial reply")
-		err = modelPlan.StorePartialReply(repo, planId, branch, auth.User.Id, auth.OrgId)
-		return err
+		return modelPlan.StorePartialReply(repo, plan, branch, auth.User.Id, auth.OrgId)
 	})
+}
 
-	if err != nil {
-		log.Printf("Error storing partial reply: %v\n", err)
-		http.Error(w, "Error storing partial reply", http.StatusInternalServerError)
+// stopPlanIfAbandoned stops a plan whose client disconnected without sending it to the background and didn't reconnect
+func stopPlanIfAbandoned(plan *db.Plan, branch string, auth *types.ServerAuth) {
+	planId := plan.Id
+
+	if !modelPlan.ShouldStopAfterDisconnect(planId, branch) {
 		return
 	}
+
+	log.Printf("Client didn't reconnect to plan %s on branch %s - stopping plan\n", planId, branch)
+
+	ctx, cancel := context.WithTimeout(shutdown.ShutdownCtx, 10*time.Second)
+	defer cancel()
+
+	err := stopPlan(ctx, plan, branch, auth)
+	if err != nil {
+		log.Printf("Error storing partial reply for abandoned plan: %v\n", err)
+	}
 }
 
 func RespondMissingFileHandler(w http.ResponseWriter, r *http.Request) {
@@ -458,10 +527,21 @@ func AutoLoadContextHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// load context messages can be re-sent on timeout, so only the first response for the message the stream is waiting on is used
+	loadContextId, accepted := modelPlan.AcceptAutoLoadContext(planId, branch, r.Header.Get(shared.LoadContextIdHeader))
+	if !accepted {
+		log.Printf("AutoLoadContextHandler - no stream waiting for auto load context %q, ignoring response\n", r.Header.Get(shared.LoadContextIdHeader))
+		http.Error(w, "No stream waiting for this auto load context response", http.StatusConflict)
+		return
+	}
+
 	var err error
 	defer func() {
 		if err == nil {
-			active.AutoLoadContextCh <- struct{}{}
+			select {
+			case active.AutoLoadContextCh <- loadContextId:
+			case <-active.Ctx.Done():
+			}
 		} else {
 			active.StreamDoneCh <- &shared.ApiError{
 				Type:   shared.ApiErrorTypeOther,
@@ -36,6 +36,7 @@ type WillSendModelRequestParams struct {
 	IsUserPrompt bool
 	ModelTag     shared.ModelTag
 	ModelId      shared.ModelId
+	TraceId      string
 }
 
 type DidSendModelRequestParams struct {
@@ -59,6 +60,7 @@ type DidSendModelRequestParams struct {
 	HadError        bool
 	NoReportedUsage bool
 	SessionId       string
+	TraceId         string
 
 	RequestStartedAt time.Time
 	Streaming        bool
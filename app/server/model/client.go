@@ -12,6 +12,7 @@ import (
 	"os"
 	"plandex-server/db"
 	"plandex-server/types"
+	"plandex-server/utils"
 	"strings"
 	"sync"
 	"time"
@@ -30,10 +31,11 @@ const (
 	ACTIVE_STREAM_CHUNK_TIMEOUT          = time.Duration(60) * time.Second
 	USAGE_CHUNK_TIMEOUT                  = time.Duration(10) * time.Second
 	MAX_ADDITIONAL_RETRIES_WITH_FALLBACK = 1
-	MAX_RETRIES_WITHOUT_FALLBACK         = 3
 	MAX_RETRY_DELAY_SECONDS              = 10
 )
 
+var MaxRetriesWithoutFallback = utils.GetEnvIntMin("PLANDEX_MODEL_MAX_RETRIES", 3, 0)
+
 var httpClient = &http.Client{}
 
 type ClientInfo struct {
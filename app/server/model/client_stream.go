@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math/rand"
 	"plandex-server/types"
 	shared "plandex-shared"
 	"time"
@@ -274,7 +273,7 @@ func withStreamingRetries[T any](
 		log.Printf("withStreamingRetries - operation returned error: %v", err)
 
 		isFallback := fallbackRes.IsFallback
-		maxRetries := MAX_RETRIES_WITHOUT_FALLBACK
+		maxRetries := MaxRetriesWithoutFallback
 		if isFallback {
 			maxRetries = MAX_ADDITIONAL_RETRIES_WITH_FALLBACK
 		}
@@ -318,17 +317,14 @@ func withStreamingRetries[T any](
 			return resp, err
 		}
 
-		var retryDelay time.Duration
-		if modelErr != nil && modelErr.RetryAfterSeconds > 0 {
-			// if the model err has a retry after, then use that with a bit of padding
-			retryDelay = time.Duration(int(float64(modelErr.RetryAfterSeconds)*1.1)) * time.Second
-		} else {
-			// otherwise, use some jitter
-			retryDelay = time.Duration(1000+rand.Intn(200)) * time.Millisecond
-		}
+		retryDelay := GetRetryDelay(numRetry, modelErr)
 
-		log.Printf("withStreamingRetries - retrying stream in %v seconds", retryDelay)
-		time.Sleep(retryDelay)
+		log.Printf("withStreamingRetries - retrying stream in %v", retryDelay)
+		if !SleepForRetry(ctx, retryDelay) {
+			log.Printf("withStreamingRetries - context done while waiting to retry")
+			// context error is handled at the top of the loop
+			continue
+		}
 
 		if modelErr != nil && modelErr.ShouldIncrementRetry() {
 			numTotalRetry++
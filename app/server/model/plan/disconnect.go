@@ -0,0 +1,70 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"plandex-server/types"
+	"plandex-server/utils"
+	"time"
+)
+
+var clientDisconnectGracePeriod = time.Duration(utils.GetEnvIntMin("PLANDEX_CLIENT_DISCONNECT_GRACE_SECONDS", 30, 0)) * time.Second
+
+// Detach marks an active plan as sent to the background by its client, so it keeps running after the client's stream disconnects
+func Detach(planId, branch string) error {
+	active := GetActivePlan(planId, branch)
+
+	if active == nil {
+		return fmt.Errorf("no active plan with id %s", planId)
+	}
+
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		ap.Detached = true
+	})
+
+	return nil
+}
+
+// ShouldStopAfterDisconnect is called when an attached client's stream closes. It waits out the grace period, then reports whether the plan was abandoned: still running, not sent to the background, and with no client reconnected.
+func ShouldStopAfterDisconnect(planId, branch string) bool {
+	active := GetActivePlan(planId, branch)
+
+	if active == nil || isDetached(planId, branch) {
+		return false
+	}
+
+	log.Printf("Client disconnected from plan %s on branch %s without detaching - waiting %v for it to reconnect\n", planId, branch, clientDisconnectGracePeriod)
+
+	return waitForAbandonedPlan(active.Ctx, clientDisconnectGracePeriod, func() bool {
+		// a plan that's already gone is left alone
+		keepRunning := true
+		UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+			keepRunning = ap.Detached || ap.NumSubscribers() > 0
+		})
+		return keepRunning
+	})
+}
+
+// isDetached reads the active plan's Detached flag under the active plans lock, since Detach sets it from another request
+func isDetached(planId, branch string) bool {
+	detached := false
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		detached = ap.Detached
+	})
+	return detached
+}
+
+// waitForAbandonedPlan returns false as soon as the plan's context is done (it finished or was stopped), otherwise waits for the grace period and returns true unless keepRunning reports that the plan is still wanted
+func waitForAbandonedPlan(planCtx context.Context, gracePeriod time.Duration, keepRunning func() bool) bool {
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-planCtx.Done():
+		return false
+	case <-timer.C:
+	}
+
+	return !keepRunning()
+}
@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"context"
+	"plandex-server/types"
+	"strings"
+	"testing"
+	"time"
+
+	shared "plandex-shared"
+)
+
+func TestShouldStopAfterDisconnect(t *testing.T) {
+	defaultGracePeriod := clientDisconnectGracePeriod
+	clientDisconnectGracePeriod = 50 * time.Millisecond
+	defer func() {
+		clientDisconnectGracePeriod = defaultGracePeriod
+	}()
+
+	tests := []struct {
+		name string
+		// runs after the attached client disconnects mid-stream
+		afterDisconnect func(planId, branch string, active *types.ActivePlan)
+		wantStop        bool
+	}{
+		{
+			name:     "client disconnects and doesn't reconnect",
+			wantStop: true,
+		},
+		{
+			name: "client sent the plan to the background",
+			afterDisconnect: func(planId, branch string, active *types.ActivePlan) {
+				if err := Detach(planId, branch); err != nil {
+					t.Fatalf("Detach: %v", err)
+				}
+			},
+			wantStop: false,
+		},
+		{
+			name: "client reconnects during the grace period",
+			afterDisconnect: func(planId, branch string, active *types.ActivePlan) {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					SubscribePlan(context.Background(), planId, branch)
+				}()
+			},
+			wantStop: false,
+		},
+		{
+			name: "plan finishes during the grace period",
+			afterDisconnect: func(planId, branch string, active *types.ActivePlan) {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					active.CancelFn()
+				}()
+			},
+			wantStop: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planId := "plan-" + strings.ReplaceAll(tt.name, " ", "-")
+			branch := "main"
+
+			active := newTestActivePlan(t, planId, branch)
+
+			// the client is attached to the stream, then drops mid-stream
+			reqCtx, disconnect := context.WithCancel(context.Background())
+			subscriptionId, _ := SubscribePlan(reqCtx, planId, branch)
+			active.Stream(shared.StreamMessage{
+				Type:       shared.StreamMessageReply,
+				ReplyChunk: "Updating the server",
+			})
+			disconnect()
+			UnsubscribePlan(planId, branch, subscriptionId)
+
+			if tt.afterDisconnect != nil {
+				tt.afterDisconnect(planId, branch, active)
+			}
+
+			got := ShouldStopAfterDisconnect(planId, branch)
+			if got != tt.wantStop {
+				t.Errorf("ShouldStopAfterDisconnect() = %t, want %t", got, tt.wantStop)
+			}
+		})
+	}
+}
@@ -60,7 +60,7 @@ func CreateActivePlan(orgId, userId, planId, branch, prompt string, buildOnly, a
 					activePlan.CancelFn()
 					return
 				} else {
-					log.Printf("Error streaming plan %s: %v\n", planId, apiErr)
+					log.Printf("Error streaming plan %s (trace %s): %v\n", planId, activePlan.TraceId, apiErr)
 
 					go notify.NotifyErr(notify.SeverityError, fmt.Errorf("error streaming plan %s: %v", planId, apiErr))
 
@@ -69,6 +69,10 @@ func CreateActivePlan(orgId, userId, planId, branch, prompt string, buildOnly, a
 						log.Printf("Error setting plan %s status to error: %v\n", planId, err)
 					}
 
+					if apiErr.TraceId == "" {
+						apiErr.TraceId = activePlan.TraceId
+					}
+
 					log.Println("Sending error message to client")
 					activePlan.Stream(shared.StreamMessage{
 						Type:  shared.StreamMessageError,
@@ -0,0 +1,29 @@
+package plan
+
+import (
+	"context"
+	"plandex-server/shutdown"
+	"plandex-server/types"
+	"strings"
+	"testing"
+)
+
+// newTestActivePlan registers an active plan for planId and branch, and removes and cancels it when the test finishes
+func newTestActivePlan(t *testing.T, planId, branch string) *types.ActivePlan {
+	t.Helper()
+
+	if shutdown.ShutdownCtx == nil {
+		shutdown.ShutdownCtx, shutdown.ShutdownCancel = context.WithCancel(context.Background())
+	}
+
+	active := types.NewActivePlan("org", "user", planId, branch, "prompt", false, true, "session")
+	key := strings.Join([]string{planId, branch}, "|")
+	activePlans.Set(key, active)
+
+	t.Cleanup(func() {
+		activePlans.Delete(key)
+		active.CancelFn()
+	})
+
+	return active
+}
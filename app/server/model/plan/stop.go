@@ -2,7 +2,9 @@ package plan
 
 import (
 	"fmt"
+	"log"
 	"plandex-server/db"
+	"plandex-server/types"
 
 	"github.com/sashabaranov/go-openai"
 )
@@ -20,33 +22,45 @@ func Stop(planId, branch, currentUserId, currentOrgId string) error {
 	return nil
 }
 
-func StorePartialReply(repo *db.GitRepo, planId, branch, currentUserId, currentOrgId string) error {
+// StorePartialReply stores a reply that's stopped mid-stream in the conversation, including any file operations that completed before the stop, unless the plan is configured to discard it
+func StorePartialReply(repo *db.GitRepo, plan *db.Plan, branch, currentUserId, currentOrgId string) error {
+	planId := plan.Id
 	active := GetActivePlan(planId, branch)
 
 	if active == nil {
 		return fmt.Errorf("no active plan with id %s", planId)
 	}
 
-	if !active.BuildOnly && !active.RepliesFinished {
-		num := active.MessageNum + 1
-
-		userMsg := db.ConvoMessage{
-			OrgId:   currentOrgId,
-			PlanId:  planId,
-			UserId:  currentUserId,
-			Role:    openai.ChatMessageRoleAssistant,
-			Tokens:  active.NumTokens,
-			Num:     num,
-			Stopped: true,
-			Message: active.CurrentReplyContent,
-		}
-
-		_, err := db.StoreConvoMessage(repo, &userMsg, currentUserId, branch, true)
-
-		if err != nil {
-			return fmt.Errorf("error storing convo message: %v", err)
-		}
+	discard := plan.PlanConfig != nil && plan.PlanConfig.DiscardPartialReplyOnStop
+
+	msg := partialReplyMessage(active, discard, planId, currentUserId, currentOrgId)
+	if msg == nil {
+		log.Printf("StorePartialReply - not storing partial reply for plan %s (discard: %t)\n", planId, discard)
+		return nil
+	}
+
+	_, err := db.StoreConvoMessage(repo, msg, currentUserId, branch, true)
+
+	if err != nil {
+		return fmt.Errorf("error storing convo message: %v", err)
 	}
 
 	return nil
 }
+
+func partialReplyMessage(active *types.ActivePlan, discard bool, planId, currentUserId, currentOrgId string) *db.ConvoMessage {
+	if discard || active.BuildOnly || active.RepliesFinished {
+		return nil
+	}
+
+	return &db.ConvoMessage{
+		OrgId:   currentOrgId,
+		PlanId:  planId,
+		UserId:  currentUserId,
+		Role:    openai.ChatMessageRoleAssistant,
+		Tokens:  active.NumTokens,
+		Num:     active.MessageNum + 1,
+		Stopped: true,
+		Message: active.CurrentReplyContent,
+	}
+}
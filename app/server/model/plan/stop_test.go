@@ -0,0 +1,153 @@
+package plan
+
+import (
+	"plandex-server/db"
+	"plandex-server/types"
+	"strings"
+	"testing"
+
+	shared "plandex-shared"
+)
+
+func TestPartialReplyMessage(t *testing.T) {
+	content := "Updating the server.\n\n- main.go:\n```go\npackage main\n```\n"
+
+	tests := []struct {
+		name      string
+		discard   bool
+		active    *types.ActivePlan
+		wantStore bool
+	}{
+		{
+			name: "persists stopped reply by default",
+			active: &types.ActivePlan{
+				CurrentReplyContent: content,
+				NumTokens:           42,
+				MessageNum:          3,
+			},
+			wantStore: true,
+		},
+		{
+			name:    "discards stopped reply when configured",
+			discard: true,
+			active: &types.ActivePlan{
+				CurrentReplyContent: content,
+			},
+			wantStore: false,
+		},
+		{
+			name: "nothing to store for build-only plans",
+			active: &types.ActivePlan{
+				BuildOnly: true,
+			},
+			wantStore: false,
+		},
+		{
+			name: "nothing to store once replies are finished",
+			active: &types.ActivePlan{
+				CurrentReplyContent: content,
+				RepliesFinished:     true,
+			},
+			wantStore: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := partialReplyMessage(tt.active, tt.discard, "plan", "user", "org")
+
+			if !tt.wantStore {
+				if msg != nil {
+					t.Fatalf("expected no message, got %+v", msg)
+				}
+				return
+			}
+
+			if msg == nil {
+				t.Fatal("expected a message, got nil")
+			}
+			if !msg.Stopped {
+				t.Error("expected message to be marked as stopped")
+			}
+			if msg.Num != tt.active.MessageNum+1 {
+				t.Errorf("Num = %d, want %d", msg.Num, tt.active.MessageNum+1)
+			}
+			if msg.Tokens != tt.active.NumTokens {
+				t.Errorf("Tokens = %d, want %d", msg.Tokens, tt.active.NumTokens)
+			}
+		})
+	}
+}
+
+func TestStopMidStreamRetainsCompletedOperations(t *testing.T) {
+	planId := "plan-stop-mid-stream"
+	branch := "main"
+
+	active := newTestActivePlan(t, planId, branch)
+
+	state := &activeTellStreamState{
+		activePlan:     active,
+		plan:           &db.Plan{Id: planId},
+		branch:         branch,
+		req:            &shared.TellPlanRequest{BuildMode: shared.BuildModeNone},
+		authVars:       map[string]string{shared.AnthropicApiKeyEnvVar: "test"},
+		modelConfig:    &shared.ModelRoleConfig{ModelId: "anthropic/claude-sonnet-4"},
+		replyParser:    types.NewReplyParser(),
+		chunkProcessor: &chunkProcessor{},
+		currentStage:   shared.CurrentStage{TellStage: shared.TellStageImplementation},
+	}
+
+	// one file block finishes, then the plan is stopped while the next one is still streaming
+	reply := "Updating the server.\n\n" +
+		"- main.go:\n\n<PlandexBlock lang=\"go\" path=\"main.go\">\npackage main\n\nfunc main() {}\n</PlandexBlock>\n\n" +
+		"- util.go:\n\n<PlandexBlock lang=\"go\" path=\"util.go\">\npackage main\n\nfunc util() {\n\treturn\n}\n</PlandexBlock>\n"
+	stopAt := strings.Index(reply, "func util")
+
+	var chunks []string
+	for i := 0; i < len(reply); i += 5 {
+		chunks = append(chunks, reply[i:min(i+5, len(reply))])
+	}
+
+	streamed := 0
+	for _, chunk := range chunks {
+		// the stream loop stops as soon as the plan's context is cancelled
+		if active.Ctx.Err() != nil {
+			break
+		}
+
+		state.processChunk(types.ExtendedChatCompletionStreamChoice{
+			Delta: types.ExtendedChatCompletionStreamChoiceDelta{Content: chunk},
+		})
+		streamed += len(chunk)
+
+		if streamed >= stopAt && active.Ctx.Err() == nil {
+			if err := Stop(planId, branch, "user", "org"); err != nil {
+				t.Fatalf("Stop: %v", err)
+			}
+		}
+	}
+
+	if streamed >= len(reply) {
+		t.Fatal("expected the stream to stop before the reply finished")
+	}
+
+	if len(active.Operations) != 1 || active.Operations[0].Path != "main.go" {
+		t.Fatalf("expected only the completed main.go operation before the stop, got %d operations", len(active.Operations))
+	}
+
+	msg := partialReplyMessage(active, false, planId, "user", "org")
+	if msg == nil {
+		t.Fatal("expected the stopped reply to be stored")
+	}
+	if !msg.Stopped {
+		t.Error("expected message to be marked as stopped")
+	}
+
+	// continuing from the stored reply recovers the operation that completed before the stop
+	parser := types.NewReplyParser()
+	parser.AddChunk(msg.Message, true)
+	ops := parser.Read().Operations
+	if len(ops) != 1 || ops[0].Path != "main.go" || !strings.Contains(ops[0].Content, "func main() {}") {
+		t.Errorf("expected stored reply to retain the completed main.go operation, got %+v", ops)
+	}
+}
@@ -3,6 +3,7 @@ package plan
 import (
 	"fmt"
 	"log"
+	"plandex-server/db"
 	"plandex-server/types"
 	"regexp"
 	"sort"
@@ -397,6 +398,10 @@ func (state *activeTellStreamState) checkAutoLoadContext() checkAutoLoadContextR
 	// pick out all potential file paths within backticks
 	matches := pathRegex.FindAllStringSubmatch(activePlan.CurrentReplyContent, -1)
 
+	autoLoadedPaths := autoLoadedPathsSnapshot(state.plan.Id, state.branch)
+	// skip asking the client to auto load paths it was already asked for earlier in the session, unless they've since been removed from context
+	dedupeAutoLoadPaths := state.plan.PlanConfig == nil || !state.plan.PlanConfig.AutoLoadRepeatPaths
+
 	toAutoLoad := map[string]bool{}
 	toActivate := map[string]bool{}
 	toActivateOrdered := []string{}
@@ -416,8 +421,10 @@ func (state *activeTellStreamState) checkAutoLoadContext() checkAutoLoadContextR
 
 				toActivate[trimmed] = true
 				toActivateOrdered = append(toActivateOrdered, trimmed)
-				if contextsByPath[trimmed] == nil {
+				if shouldAutoLoadPath(trimmed, contextsByPath, autoLoadedPaths, dedupeAutoLoadPaths) {
 					toAutoLoad[trimmed] = true
+				} else if contextsByPath[trimmed] == nil {
+					log.Printf("Tell plan - checkAutoLoadContext - skipping %s - already requested this session\n", trimmed)
 				}
 
 			}
@@ -431,6 +438,10 @@ func (state *activeTellStreamState) checkAutoLoadContext() checkAutoLoadContextR
 
 	hasExplicitPaths := strings.Contains(activePlan.CurrentReplyContent, "### Files")
 
+	if len(toAutoLoadPaths) > 0 {
+		markAutoLoadedPaths(state.plan.Id, state.branch, toAutoLoadPaths)
+	}
+
 	log.Printf("Tell plan - checkAutoLoadContext - toAutoLoad: %v\n", toAutoLoadPaths)
 	log.Printf("Tell plan - checkAutoLoadContext - toActivate: %v\n", toActivateOrdered)
 
@@ -441,3 +452,76 @@ func (state *activeTellStreamState) checkAutoLoadContext() checkAutoLoadContextR
 		hasExplicitPaths:     hasExplicitPaths,
 	}
 }
+
+func shouldAutoLoadPath(path string, contextsByPath map[string]*db.Context, autoLoadedPaths map[string]bool, dedupe bool) bool {
+	if contextsByPath[path] != nil {
+		return false
+	}
+
+	// the client was already asked for this path but it didn't end up in context (skipped, too large, etc.) -- asking again would just repeat the round trip
+	if dedupe && autoLoadedPaths[path] {
+		return false
+	}
+
+	return true
+}
+
+// AutoLoadedPaths and RemovedContextIds can be updated by DeleteContextHandler while a tell is streaming, so they're only ever read or written under the active plans lock
+
+func autoLoadedPathsSnapshot(planId, branch string) map[string]bool {
+	snapshot := map[string]bool{}
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		for path := range ap.AutoLoadedPaths {
+			snapshot[path] = true
+		}
+	})
+	return snapshot
+}
+
+func markAutoLoadedPaths(planId, branch string, paths []string) {
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		for _, path := range paths {
+			ap.AutoLoadedPaths[path] = true
+		}
+	})
+}
+
+// RemoveActivePlanContexts records contexts removed while a plan is streaming. The stream drops them from its loaded context at the start of its next iteration (see applyRemovedContexts), after which their paths can be auto loaded again.
+func RemoveActivePlanContexts(planId, branch string, contexts []*db.Context) {
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		for _, context := range contexts {
+			ap.RemovedContextIds[context.Id] = true
+			if context.FilePath != "" {
+				delete(ap.AutoLoadedPaths, context.FilePath)
+			}
+		}
+	})
+}
+
+// applyRemovedContexts runs on the stream's goroutine between iterations, since the stream reads Contexts and ContextsByPath without the lock. ContextsByPath is replaced rather than modified in place because builds can still be reading it.
+func applyRemovedContexts(planId, branch string) {
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		if len(ap.RemovedContextIds) == 0 {
+			return
+		}
+
+		contexts := make([]*db.Context, 0, len(ap.Contexts))
+		contextsByPath := make(map[string]*db.Context, len(ap.ContextsByPath))
+		for _, context := range ap.Contexts {
+			if ap.RemovedContextIds[context.Id] {
+				continue
+			}
+			contexts = append(contexts, context)
+		}
+		for path, context := range ap.ContextsByPath {
+			if ap.RemovedContextIds[context.Id] {
+				continue
+			}
+			contextsByPath[path] = context
+		}
+
+		ap.Contexts = contexts
+		ap.ContextsByPath = contextsByPath
+		ap.RemovedContextIds = map[string]bool{}
+	})
+}
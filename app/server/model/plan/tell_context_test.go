@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"plandex-server/db"
+	"plandex-server/types"
+	"testing"
+)
+
+func TestAutoLoadPathsAcrossIterations(t *testing.T) {
+	planId := "plan-auto-load"
+	branch := "main"
+
+	active := newTestActivePlan(t, planId, branch)
+
+	// adds a context to the active plan, as AutoLoadContextHandler does once the client loads it
+	addContext := func(id, path string) *db.Context {
+		context := &db.Context{Id: id, FilePath: path}
+		UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+			ap.Contexts = append(ap.Contexts, context)
+			ap.ContextsByPath[path] = context
+		})
+		return context
+	}
+
+	// mirrors checkAutoLoadContext: decide against a snapshot, then mark requested paths on the active plan
+	request := func(path string) bool {
+		if shouldAutoLoadPath(path, active.ContextsByPath, autoLoadedPathsSnapshot(planId, branch), true) {
+			markAutoLoadedPaths(planId, branch, []string{path})
+			return true
+		}
+		return false
+	}
+
+	addContext("ctx-loaded", "loaded.go")
+
+	if request("loaded.go") {
+		t.Error("path already in context should not be auto loaded")
+	}
+
+	// iteration 1: the model mentions a new path, the client is asked for it and loads it
+	if !request("new.go") {
+		t.Fatal("expected new path to be auto loaded on first mention")
+	}
+	newContext := addContext("ctx-new", "new.go")
+
+	// iteration 2: the model mentions it again, along with a path the client skips
+	if request("new.go") {
+		t.Error("path already loaded into context should not be requested again")
+	}
+	if !request("skipped.go") {
+		t.Fatal("expected new path to be auto loaded on first mention")
+	}
+
+	// iteration 3: the skipped path never made it into context
+	if request("skipped.go") {
+		t.Error("path auto loaded earlier in the session should not be requested again")
+	}
+
+	// with dedupe disabled, previously requested paths are requested again
+	if !shouldAutoLoadPath("skipped.go", active.ContextsByPath, autoLoadedPathsSnapshot(planId, branch), false) {
+		t.Error("expected path to be requested again when dedupe is disabled")
+	}
+
+	// the user removes new.go from context mid-stream, as DeleteContextHandler does
+	RemoveActivePlanContexts(planId, branch, []*db.Context{newContext})
+
+	// the current iteration's loaded context is left alone
+	if active.ContextsByPath["new.go"] == nil {
+		t.Error("RemoveActivePlanContexts should not modify ContextsByPath")
+	}
+
+	// iteration 4: the stream drops the removed context before reusing the loaded context
+	applyRemovedContexts(planId, branch)
+
+	if active.ContextsByPath["new.go"] != nil {
+		t.Error("removed context should be dropped from ContextsByPath")
+	}
+	if len(active.Contexts) != 1 || active.Contexts[0].FilePath != "loaded.go" {
+		t.Errorf("expected Contexts to match ContextsByPath after removal, got %d contexts", len(active.Contexts))
+	}
+	if active.ContextsByPath["loaded.go"] == nil {
+		t.Error("contexts that weren't removed should be kept")
+	}
+
+	if !request("new.go") {
+		t.Error("path removed from context should be auto loaded again")
+	}
+
+	// plans that aren't active are ignored
+	RemoveActivePlanContexts("other-plan", branch, []*db.Context{newContext})
+}
@@ -81,6 +81,7 @@ type execTellPlanParams struct {
 	missingFileResponse        shared.RespondMissingFileChoice
 	shouldBuildPending         bool
 	unfinishedSubtaskReasoning string
+	notifiedFallbackModel      string
 }
 
 func execTellPlan(params execTellPlanParams) {
@@ -94,6 +95,7 @@ func execTellPlan(params execTellPlanParams) {
 	missingFileResponse := params.missingFileResponse
 	shouldBuildPending := params.shouldBuildPending
 	unfinishedSubtaskReasoning := params.unfinishedSubtaskReasoning
+	notifiedFallbackModel := params.notifiedFallbackModel
 
 	log.Printf("[TellExec] Starting iteration %d for plan %s on branch %s", iteration, plan.Id, branch)
 
@@ -107,6 +109,8 @@ func execTellPlan(params execTellPlanParams) {
 		return
 	}
 
+	log.Printf("[TellExec] Iteration %d trace id: %s", iteration, active.TraceId)
+
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("execTellPlan: Panic: %v\n%s\n", r, string(debug.Stack()))
@@ -165,6 +169,8 @@ func execTellPlan(params execTellPlanParams) {
 		branch:              branch,
 		iteration:           iteration,
 		missingFileResponse: missingFileResponse,
+
+		notifiedFallbackModel: notifiedFallbackModel,
 	}
 
 	log.Println("execTellPlan - Loading tell plan")
@@ -427,6 +433,9 @@ func execTellPlan(params execTellPlanParams) {
 
 	state.baseModelConfig = baseModelConfig
 
+	// let the client know if the request was routed to a large context model
+	state.streamModelFallback(tentativeModelConfig.GetBaseModelConfig(authVars, state.settings, state.orgUserConfig), baseModelConfig, shared.FallbackTypeContext)
+
 	// if the model doesn't support cache control, remove the cache control spec from the messages
 	if !baseModelConfig.SupportsCacheControl {
 		for i := range state.messages {
@@ -471,6 +480,7 @@ func execTellPlan(params execTellPlanParams) {
 			ModelId:      baseModelConfig.ModelId,
 			ModelTag:     baseModelConfig.ModelTag,
 			IsUserPrompt: true,
+			TraceId:      active.TraceId,
 		},
 	})
 	if apiErr != nil {
@@ -507,6 +517,10 @@ func (state *activeTellStreamState) doTellRequest() {
 		state.didProviderFallback = true
 	}
 
+	if fallbackRes.IsFallback {
+		state.streamModelFallback(state.modelConfig.GetBaseModelConfig(authVars, state.settings, state.orgUserConfig), baseModelConfig, fallbackRes.FallbackType)
+	}
+
 	// log.Println("Stop:", stop)
 	// spew.Dump(state.messages)
 
@@ -561,8 +575,8 @@ func (state *activeTellStreamState) doTellRequest() {
 	// 	log.Printf("Error marshaling model request to JSON: %v\n", err)
 	// }
 
-	log.Printf("[Tell] doTellRequest retry=%d fallbackRetry=%d using model=%s",
-		state.numErrorRetry, state.numFallbackRetry, baseModelConfig.ModelName)
+	log.Printf("[Tell] doTellRequest retry=%d fallbackRetry=%d using model=%s trace=%s",
+		state.numErrorRetry, state.numFallbackRetry, baseModelConfig.ModelName, active.TraceId)
 
 	// start the stream
 	stream, err := model.CreateChatCompletionStream(clients, authVars, modelConfig, state.settings, state.orgUserConfig, state.currentOrgId, state.currentUserId, active.ModelStreamCtx, modelReq)
@@ -596,6 +610,7 @@ func (state *activeTellStreamState) dryRunCalculateTokensWithoutContext(tentativ
 		settings:            state.settings,
 		currentStage:        state.currentStage,
 		subtasks:            state.subtasks,
+		maxSubtasks:         state.maxSubtasks,
 		currentSubtask:      state.currentSubtask,
 		convo:               state.convo,
 		summaries:           state.summaries,
@@ -0,0 +1,70 @@
+package plan
+
+import (
+	"fmt"
+	"log"
+
+	shared "plandex-shared"
+)
+
+// streamModelFallback lets the client know when a request is handled by a different model than the one configured for its role, and when it's back on the configured model. It runs for every iteration and retry, so it only streams (and logs) when the model differs from the one the client was last told about.
+func (state *activeTellStreamState) streamModelFallback(from, to *shared.BaseModelConfig, reason shared.FallbackType) {
+	fallback, changed := state.nextModelFallback(from, to, reason)
+	if !changed {
+		return
+	}
+
+	if fallback == nil {
+		log.Printf("Tell plan - back on configured model | trace id: %s\n", state.activePlan.TraceId)
+	} else {
+		log.Printf("Tell plan - model fallback (%s): %s -> %s | trace id: %s\n", fallback.Reason, fallback.FromModel, fallback.ToModel, state.activePlan.TraceId)
+	}
+
+	// a nil fallback clears the client's fallback notice
+	state.activePlan.Stream(shared.StreamMessage{
+		Type:          shared.StreamMessageModelFallback,
+		ModelFallback: fallback,
+	})
+}
+
+// nextModelFallback records the fallback in effect for the current reply, and reports whether that changes what the client was last told -- a nil fallback with changed set means the request is back on the configured model
+func (state *activeTellStreamState) nextModelFallback(from, to *shared.BaseModelConfig, reason shared.FallbackType) (*shared.ModelFallback, bool) {
+	fallback := newModelFallback(from, to, reason)
+	state.modelFallback = fallback
+
+	if fallback == nil {
+		if state.notifiedFallbackModel == "" {
+			return nil, false
+		}
+		state.notifiedFallbackModel = ""
+		return nil, true
+	}
+
+	if fallback.ToModel == state.notifiedFallbackModel {
+		return nil, false
+	}
+
+	state.notifiedFallbackModel = fallback.ToModel
+	return fallback, true
+}
+
+// newModelFallback returns nil if the request didn't actually move to a different model or provider
+func newModelFallback(from, to *shared.BaseModelConfig, reason shared.FallbackType) *shared.ModelFallback {
+	if from == nil || to == nil {
+		return nil
+	}
+
+	if from.ModelId == to.ModelId && from.Provider == to.Provider {
+		return nil
+	}
+
+	return &shared.ModelFallback{
+		FromModel: modelFallbackLabel(from),
+		ToModel:   modelFallbackLabel(to),
+		Reason:    reason,
+	}
+}
+
+func modelFallbackLabel(config *shared.BaseModelConfig) string {
+	return fmt.Sprintf("%s (%s)", config.ModelName, config.Provider)
+}
@@ -0,0 +1,134 @@
+package plan
+
+import (
+	"testing"
+
+	shared "plandex-shared"
+)
+
+func TestNewModelFallback(t *testing.T) {
+	baseModel := func(id shared.ModelId, provider shared.ModelProvider, name shared.ModelName) *shared.BaseModelConfig {
+		config := &shared.BaseModelConfig{ModelId: id}
+		config.Provider = provider
+		config.ModelName = name
+		return config
+	}
+
+	sonnet := baseModel("anthropic/claude-sonnet", shared.ModelProviderAnthropic, "claude-sonnet")
+	sonnetOpenRouter := baseModel("anthropic/claude-sonnet", shared.ModelProviderOpenRouter, "anthropic/claude-sonnet")
+	gemini := baseModel("google/gemini-pro", shared.ModelProviderGoogleAIStudio, "gemini-pro")
+
+	tests := []struct {
+		name     string
+		from     *shared.BaseModelConfig
+		to       *shared.BaseModelConfig
+		reason   shared.FallbackType
+		wantNil  bool
+		wantFrom string
+		wantTo   string
+	}{
+		{
+			name:    "same model and provider",
+			from:    sonnet,
+			to:      sonnet,
+			reason:  shared.FallbackTypeContext,
+			wantNil: true,
+		},
+		{
+			name:     "large context fallback",
+			from:     sonnet,
+			to:       gemini,
+			reason:   shared.FallbackTypeContext,
+			wantFrom: "claude-sonnet (anthropic)",
+			wantTo:   "gemini-pro (google-ai-studio)",
+		},
+		{
+			name:     "provider fallback keeps the model",
+			from:     sonnet,
+			to:       sonnetOpenRouter,
+			reason:   shared.FallbackTypeProvider,
+			wantFrom: "claude-sonnet (anthropic)",
+			wantTo:   "anthropic/claude-sonnet (openrouter)",
+		},
+		{
+			name:    "missing config",
+			from:    nil,
+			to:      gemini,
+			reason:  shared.FallbackTypeError,
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newModelFallback(tt.from, tt.to, tt.reason)
+
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("expected no fallback, got %+v", got)
+				}
+				return
+			}
+
+			if got == nil {
+				t.Fatal("expected a fallback, got nil")
+			}
+			if got.FromModel != tt.wantFrom || got.ToModel != tt.wantTo {
+				t.Errorf("got %q -> %q, want %q -> %q", got.FromModel, got.ToModel, tt.wantFrom, tt.wantTo)
+			}
+			if got.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestNextModelFallbackAcrossRetries(t *testing.T) {
+	baseModel := func(id shared.ModelId, provider shared.ModelProvider) *shared.BaseModelConfig {
+		config := &shared.BaseModelConfig{ModelId: id}
+		config.Provider = provider
+		config.ModelName = shared.ModelName(id)
+		return config
+	}
+
+	sonnet := baseModel("anthropic/claude-sonnet", shared.ModelProviderAnthropic)
+	sonnetOpenRouter := baseModel("anthropic/claude-sonnet", shared.ModelProviderOpenRouter)
+	gemini := baseModel("google/gemini-pro", shared.ModelProviderGoogleAIStudio)
+
+	state := &activeTellStreamState{}
+
+	steps := []struct {
+		name       string
+		from       *shared.BaseModelConfig
+		to         *shared.BaseModelConfig
+		reason     shared.FallbackType
+		wantNotify bool
+		wantClear  bool // client is told the plan is back on the configured model
+	}{
+		{name: "configured model", from: sonnet, to: sonnet, reason: shared.FallbackTypeContext},
+		{name: "provider fallback", from: sonnet, to: sonnetOpenRouter, reason: shared.FallbackTypeProvider, wantNotify: true},
+		{name: "retry on the same fallback", from: sonnet, to: sonnetOpenRouter, reason: shared.FallbackTypeProvider},
+		{name: "next iteration on the same fallback", from: sonnet, to: sonnetOpenRouter, reason: shared.FallbackTypeProvider},
+		{name: "back on the configured model", from: sonnet, to: sonnet, reason: shared.FallbackTypeContext, wantNotify: true, wantClear: true},
+		{name: "still on the configured model", from: sonnet, to: sonnet, reason: shared.FallbackTypeContext},
+		{name: "same fallback again", from: sonnet, to: sonnetOpenRouter, reason: shared.FallbackTypeProvider, wantNotify: true},
+		{name: "large context fallback", from: sonnet, to: gemini, reason: shared.FallbackTypeContext, wantNotify: true},
+		{name: "retry on the large context model", from: sonnet, to: gemini, reason: shared.FallbackTypeContext},
+	}
+
+	for _, step := range steps {
+		got, changed := state.nextModelFallback(step.from, step.to, step.reason)
+		if changed != step.wantNotify {
+			t.Errorf("%s: notify = %t, want %t", step.name, changed, step.wantNotify)
+		}
+		if changed && (got == nil) != step.wantClear {
+			t.Errorf("%s: cleared = %t, want %t", step.name, got == nil, step.wantClear)
+		}
+
+		// the fallback in effect is recorded for the stored reply whether or not the client is notified
+		wantRecorded := newModelFallback(step.from, step.to, step.reason)
+		if (state.modelFallback == nil) != (wantRecorded == nil) || (wantRecorded != nil && *state.modelFallback != *wantRecorded) {
+			t.Errorf("%s: recorded fallback = %+v, want %+v", step.name, state.modelFallback, wantRecorded)
+		}
+	}
+}
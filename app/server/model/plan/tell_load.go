@@ -39,6 +39,9 @@ func (state *activeTellStreamState) loadTellPlan() error {
 	lockScope := db.LockScopeWrite
 	if iteration > 0 || missingFileResponse != "" {
 		lockScope = db.LockScopeRead
+
+		// later iterations reuse the active plan's loaded context, so drop any contexts removed since the last iteration
+		applyRemovedContexts(planId, branch)
 	}
 
 	var modelContext []*db.Context
@@ -305,6 +308,7 @@ func (state *activeTellStreamState) loadTellPlan() error {
 				return
 			}
 			subtasks = res
+
 			errCh <- nil
 		}()
 
@@ -357,6 +361,11 @@ func (state *activeTellStreamState) loadTellPlan() error {
 	state.settings = settings
 	state.currentPlanState = currentPlan
 	state.subtasks = subtasks
+	if plan.PlanConfig != nil {
+		state.maxSubtasks = plan.PlanConfig.GetMaxSubtasks()
+	} else {
+		state.maxSubtasks = shared.PlanConfig{}.GetMaxSubtasks()
+	}
 
 	for _, subtask := range state.subtasks {
 		if !subtask.IsFinished {
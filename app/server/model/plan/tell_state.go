@@ -44,6 +44,7 @@ type activeTellStreamState struct {
 	totalRequestTokens    int
 	settings              *shared.PlanSettings
 	subtasks              []*db.Subtask
+	maxSubtasks           int
 	currentSubtask        *db.Subtask
 	hasAssistantReply     bool
 	currentStage          shared.CurrentStage
@@ -66,6 +67,11 @@ type activeTellStreamState struct {
 	modelErr            *shared.ModelError
 	noCacheSupportErr   bool
 	didProviderFallback bool
+
+	// fallback in effect for the current reply, stored with it so it can be inspected later
+	modelFallback *shared.ModelFallback
+	// model the client was last told the tell fell back to -- empty while it's on the configured model
+	notifiedFallbackModel string
 }
 
 type chunkProcessor struct {
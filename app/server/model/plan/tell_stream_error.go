@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math/rand"
 	"net/http"
 	"plandex-server/db"
 	"plandex-server/model"
@@ -53,10 +52,12 @@ func (state *activeTellStreamState) onError(params onErrorParams) onErrorResult
 		}
 	}
 
+	log.Printf("tellStream onError - trace id: %s\n", active.TraceId)
+
 	canRetry := params.canRetry
 	isFallback := state.fallbackRes.IsFallback
 
-	maxRetries := model.MAX_RETRIES_WITHOUT_FALLBACK
+	maxRetries := model.MaxRetriesWithoutFallback
 	if isFallback {
 		maxRetries = model.MAX_ADDITIONAL_RETRIES_WITH_FALLBACK
 	}
@@ -87,6 +88,7 @@ func (state *activeTellStreamState) onError(params onErrorParams) onErrorResult
 				// otherwise, continue to retry logic
 				canRetry = true
 				newFallback = true
+				compareRetries = 0
 			}
 		}
 	}
@@ -108,14 +110,7 @@ func (state *activeTellStreamState) onError(params onErrorParams) onErrorResult
 
 		active.ResetModelCtx()
 
-		var retryDelay time.Duration
-		if modelErr != nil && modelErr.RetryAfterSeconds > 0 {
-			// if the model err has a retry after, then use that with a bit of padding
-			retryDelay = time.Duration(int(float64(modelErr.RetryAfterSeconds)*1.1)) * time.Second
-		} else {
-			// otherwise, use some jitter
-			retryDelay = time.Duration(1000+rand.Intn(200)) * time.Millisecond
-		}
+		retryDelay := model.GetRetryDelay(compareRetries, modelErr)
 
 		cacheSupportErr := modelErr != nil && modelErr.Kind == shared.ErrCacheSupport
 
@@ -125,7 +120,13 @@ func (state *activeTellStreamState) onError(params onErrorParams) onErrorResult
 		}
 
 		log.Printf("tellStream onError - Retry %d/%d - Retrying stream in %v", numErrorRetry, maxRetries, retryDelay)
-		time.Sleep(retryDelay)
+		if !model.SleepForRetry(active.ModelStreamCtx, retryDelay) {
+			// stopped by the user or the plan was cancelled while waiting -- the stop handler takes care of the client
+			log.Printf("tellStream onError - context cancelled while waiting to retry for plan ID %s on branch %s\n", planId, branch)
+			return onErrorResult{
+				shouldReturn: true,
+			}
+		}
 
 		state.numErrorRetry = numErrorRetry
 		if isFallback && !newFallback && modelErr != nil && modelErr.ShouldIncrementRetry() {
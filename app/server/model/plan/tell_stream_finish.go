@@ -1,22 +1,31 @@
 package plan
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"plandex-server/db"
 	"plandex-server/notify"
 	"plandex-server/types"
+	"plandex-server/utils"
 	"runtime/debug"
 	"time"
 
 	shared "plandex-shared"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/google/uuid"
 )
 
 const MaxAutoContinueIterations = 200
 
+// the client gets this many chances to respond to a load context message, each with its own timeout
+var (
+	autoLoadContextMaxAttempts    = utils.GetEnvIntMin("PLANDEX_AUTO_LOAD_CONTEXT_ATTEMPTS", 3, 1)
+	autoLoadContextAttemptTimeout = time.Duration(utils.GetEnvIntMin("PLANDEX_AUTO_LOAD_CONTEXT_TIMEOUT_SECONDS", 30, 1)) * time.Second
+)
+
 type handleStreamFinishedResult struct {
 	shouldContinueMainLoop bool
 	shouldReturn           bool
@@ -77,6 +86,14 @@ func (state *activeTellStreamState) handleStreamFinished() handleStreamFinishedR
 	hasExplicitTasks := checkNewSubtasksResult.hasExplicitTasks
 	addedSubtasks := checkNewSubtasksResult.newSubtasks
 
+	if len(checkNewSubtasksResult.droppedSubtasks) > 0 {
+		active.Stream(shared.StreamMessage{
+			Type:       shared.StreamMessageReply,
+			ReplyChunk: fmt.Sprintf("\n\n> **Note:** this plan has reached the maximum of %d subtasks, so %d proposed subtasks weren't added.\n", state.maxSubtasks, len(checkNewSubtasksResult.droppedSubtasks)),
+		})
+		active.FlushStreamBuffer()
+	}
+
 	checkRemoveSubtasksResult := state.checkRemoveSubtasks()
 
 	removedSubtasks := checkRemoveSubtasksResult.removedSubtasks
@@ -162,42 +179,54 @@ func (state *activeTellStreamState) handleStreamFinished() handleStreamFinishedR
 	if len(autoLoadPaths) > 0 {
 		log.Println("Sending stream message to load context files")
 
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					log.Printf("panic streaming auto-load context: %v\n%s", r, debug.Stack())
-					go notify.NotifyErr(notify.SeverityError, fmt.Errorf("panic streaming auto-load context: %v\n%s", r, debug.Stack()))
+		// every attempt carries the same id, so the client can ignore a re-send it's already handling and a late response can't satisfy a later wait
+		loadContextId, doneWaiting := expectAutoLoadContext(planId, branch)
+
+		sendLoadContext := func(attempt int) {
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("panic streaming auto-load context: %v\n%s", r, debug.Stack())
+						go notify.NotifyErr(notify.SeverityError, fmt.Errorf("panic streaming auto-load context: %v\n%s", r, debug.Stack()))
+					}
+				}()
+
+				if attempt > 1 {
+					log.Printf("Re-sending stream message to load context files (attempt %d/%d)\n", attempt, autoLoadContextMaxAttempts)
 				}
+
+				active.Stream(shared.StreamMessage{
+					Type:             shared.StreamMessageLoadContext,
+					LoadContextFiles: autoLoadPaths,
+					LoadContextId:    loadContextId,
+				})
+				active.FlushStreamBuffer()
 			}()
+		}
 
-			active.Stream(shared.StreamMessage{
-				Type:             shared.StreamMessageLoadContext,
-				LoadContextFiles: autoLoadPaths,
-			})
-			active.FlushStreamBuffer()
-		}()
+		log.Printf("Waiting for client to auto load context (%d attempts, %s timeout each)\n", autoLoadContextMaxAttempts, autoLoadContextAttemptTimeout)
 
-		log.Println("Waiting for client to auto load context (30s timeout)")
+		waitRes := waitForAutoLoadContext(active.Ctx, active.AutoLoadContextCh, loadContextId, sendLoadContext, autoLoadContextMaxAttempts, autoLoadContextAttemptTimeout)
+		doneWaiting()
 
-		select {
-		case <-active.Ctx.Done():
+		switch waitRes {
+		case autoLoadContextCancelled:
 			log.Println("Context cancelled while waiting for auto load context")
 			state.execHookOnStop(false)
 			return handleStreamFinishedResult{
 				shouldContinueMainLoop: false,
 				shouldReturn:           true,
 			}
-		case <-time.After(30 * time.Second):
+		case autoLoadContextTimedOut:
 			log.Println("Timeout waiting for auto load context")
 			res := state.onError(onErrorParams{
-				streamErr: fmt.Errorf("timeout waiting for auto load context response"),
+				streamErr: fmt.Errorf("timeout waiting for auto load context response after %d attempts", autoLoadContextMaxAttempts),
 				storeDesc: true,
 			})
 			return handleStreamFinishedResult{
 				shouldContinueMainLoop: res.shouldContinueMainLoop,
 				shouldReturn:           res.shouldReturn,
 			}
-		case <-active.AutoLoadContextCh:
 		}
 	}
 
@@ -220,6 +249,8 @@ func (state *activeTellStreamState) handleStreamFinished() handleStreamFinishedR
 			req:       req,
 			iteration: iteration + 1,
 			authVars:  authVars,
+
+			notifiedFallbackModel: state.notifiedFallbackModel,
 		})
 	} else {
 		var buildFinished bool
@@ -275,3 +306,76 @@ func (state *activeTellStreamState) handleStreamFinished() handleStreamFinishedR
 
 	return handleStreamFinishedResult{}
 }
+
+type autoLoadContextWaitResult int
+
+const (
+	autoLoadContextLoaded autoLoadContextWaitResult = iota
+	autoLoadContextCancelled
+	autoLoadContextTimedOut
+)
+
+// expectAutoLoadContext generates the id the client must echo back for its auto load context response to be accepted. The returned func stops accepting responses once the stream is done waiting.
+func expectAutoLoadContext(planId, branch string) (string, func()) {
+	loadContextId := uuid.New().String()
+
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		ap.AutoLoadContextId = loadContextId
+	})
+
+	return loadContextId, func() {
+		UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+			if ap.AutoLoadContextId == loadContextId {
+				ap.AutoLoadContextId = ""
+			}
+		})
+	}
+}
+
+// AcceptAutoLoadContext reports whether an auto load context response is for the load context message the stream is currently waiting on, and returns the id to hand to the waiting stream. Each id is only accepted once, so duplicate or late responses are dropped. Clients that predate load context ids don't send one, so a response without an id is matched to the current wait.
+func AcceptAutoLoadContext(planId, branch, loadContextId string) (string, bool) {
+	var acceptedId string
+	UpdateActivePlan(planId, branch, func(ap *types.ActivePlan) {
+		if ap.AutoLoadContextId == "" {
+			return
+		}
+		if loadContextId == "" || ap.AutoLoadContextId == loadContextId {
+			acceptedId = ap.AutoLoadContextId
+			ap.AutoLoadContextId = ""
+		}
+	})
+	return acceptedId, acceptedId != ""
+}
+
+// waitForAutoLoadContext calls send, then waits for the client's response with a matching id on loadedCh -- responses for any other id are ignored. If an attempt times out, the load context message is sent again, up to maxAttempts, so a single dropped message doesn't abort the plan.
+func waitForAutoLoadContext(ctx context.Context, loadedCh <-chan string, loadContextId string, send func(attempt int), maxAttempts int, attemptTimeout time.Duration) autoLoadContextWaitResult {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		send(attempt)
+
+		timer := time.NewTimer(attemptTimeout)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return autoLoadContextCancelled
+			case id := <-loadedCh:
+				if id != loadContextId {
+					log.Printf("Ignoring auto load context response %s while waiting for %s\n", id, loadContextId)
+					continue
+				}
+				timer.Stop()
+				return autoLoadContextLoaded
+			case <-timer.C:
+				log.Printf("Timeout waiting for auto load context (attempt %d/%d)\n", attempt, maxAttempts)
+				break wait
+			}
+		}
+	}
+
+	return autoLoadContextTimedOut
+}
@@ -0,0 +1,162 @@
+package plan
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWaitForAutoLoadContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		respondOn    int  // attempt on which the client responds; 0 means never
+		staleFirst   bool // a response for an earlier load context message arrives first
+		maxAttempts  int
+		cancel       bool
+		want         autoLoadContextWaitResult
+		wantAttempts int
+	}{
+		{
+			name:         "loads on first attempt",
+			respondOn:    1,
+			maxAttempts:  3,
+			want:         autoLoadContextLoaded,
+			wantAttempts: 1,
+		},
+		{
+			name:         "first message missed, retry succeeds",
+			respondOn:    2,
+			maxAttempts:  3,
+			want:         autoLoadContextLoaded,
+			wantAttempts: 2,
+		},
+		{
+			name:         "stale response from an earlier wait is ignored",
+			respondOn:    1,
+			staleFirst:   true,
+			maxAttempts:  3,
+			want:         autoLoadContextLoaded,
+			wantAttempts: 1,
+		},
+		{
+			name:         "only a stale response arrives",
+			respondOn:    0,
+			staleFirst:   true,
+			maxAttempts:  2,
+			want:         autoLoadContextTimedOut,
+			wantAttempts: 2,
+		},
+		{
+			name:         "times out after max attempts",
+			respondOn:    0,
+			maxAttempts:  2,
+			want:         autoLoadContextTimedOut,
+			wantAttempts: 2,
+		},
+		{
+			name:         "zero max attempts still sends once",
+			respondOn:    0,
+			maxAttempts:  0,
+			want:         autoLoadContextTimedOut,
+			wantAttempts: 1,
+		},
+		{
+			name:         "cancelled context",
+			respondOn:    0,
+			maxAttempts:  3,
+			cancel:       true,
+			want:         autoLoadContextCancelled,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			loadedCh := make(chan string, 1)
+			attempts := 0
+
+			if tt.staleFirst {
+				loadedCh <- "earlier-load-context"
+			}
+
+			send := func(attempt int) {
+				attempts = attempt
+				if tt.cancel {
+					cancel()
+					return
+				}
+				if attempt == tt.respondOn {
+					go func() {
+						loadedCh <- "load-context"
+					}()
+				}
+			}
+
+			got := waitForAutoLoadContext(ctx, loadedCh, "load-context", send, tt.maxAttempts, 50*time.Millisecond)
+
+			if got != tt.want {
+				t.Errorf("waitForAutoLoadContext() = %v, want %v", got, tt.want)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestAcceptAutoLoadContext(t *testing.T) {
+	planId := "plan-accept-auto-load"
+	branch := "main"
+
+	newTestActivePlan(t, planId, branch)
+
+	accept := func(loadContextId string) bool {
+		_, accepted := AcceptAutoLoadContext(planId, branch, loadContextId)
+		return accepted
+	}
+
+	if accept("") {
+		t.Error("response should not be accepted while the stream isn't waiting")
+	}
+
+	firstId, doneWaiting := expectAutoLoadContext(planId, branch)
+
+	if accept("other") {
+		t.Error("response for a different load context message should not be accepted")
+	}
+	if !accept(firstId) {
+		t.Fatal("expected response for the current load context message to be accepted")
+	}
+	if accept(firstId) {
+		t.Error("duplicate response should not be accepted")
+	}
+	doneWaiting()
+
+	secondId, doneWaiting := expectAutoLoadContext(planId, branch)
+	if secondId == firstId {
+		t.Fatal("expected a new id for each wait")
+	}
+	doneWaiting()
+
+	if accept(secondId) {
+		t.Error("late response after the stream stopped waiting should not be accepted")
+	}
+
+	// clients that predate load context ids respond without one
+	thirdId, doneWaiting := expectAutoLoadContext(planId, branch)
+	defer doneWaiting()
+
+	acceptedId, accepted := AcceptAutoLoadContext(planId, branch, "")
+	if !accepted {
+		t.Fatal("expected response without an id to be accepted for the current wait")
+	}
+	if acceptedId != thirdId {
+		t.Errorf("accepted id = %q, want %q", acceptedId, thirdId)
+	}
+	if accept("") {
+		t.Error("duplicate response without an id should not be accepted")
+	}
+}
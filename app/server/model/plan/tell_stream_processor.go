@@ -720,6 +720,8 @@ func (state *activeTellStreamState) handleMissingFile(content, currentFile, bloc
 		iteration:           iteration, // keep the same iteration
 		missingFileResponse: userChoice,
 		authVars:            authVars,
+
+		notifiedFallbackModel: state.notifiedFallbackModel,
 	})
 
 	return processChunkResult{shouldReturn: true}
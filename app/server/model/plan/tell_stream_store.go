@@ -284,6 +284,7 @@ func (state *activeTellStreamState) storeAssistantReply(repo *db.GitRepo, params
 		ActivatedPaths:        activatePaths,
 		ActivatedPathsOrdered: activatePathsOrdered,
 		RemovedSubtasks:       removedSubtasks,
+		ModelFallback:         state.modelFallback,
 	}
 
 	commitMsg, err := db.StoreConvoMessage(repo, &assistantMsg, auth.User.Id, branch, false)
@@ -5,8 +5,11 @@ import (
 	"log"
 	"plandex-server/hooks"
 	"plandex-server/notify"
+	"plandex-server/types"
 	"runtime/debug"
 
+	shared "plandex-shared"
+
 	"github.com/davecgh/go-spew/spew"
 	"github.com/sashabaranov/go-openai"
 )
@@ -26,6 +29,20 @@ func (state *activeTellStreamState) handleUsageChunk(usage *openai.Usage) {
 
 	sessionId := state.activePlan.SessionId
 
+	// usage is reported once per model request, so accumulate it across iterations and stream the running total to the client
+	var tokenUsage shared.TokenUsage
+	UpdateActivePlan(plan.Id, state.branch, func(ap *types.ActivePlan) {
+		ap.TokenUsage.PromptTokens += usage.PromptTokens
+		ap.TokenUsage.CompletionTokens += usage.CompletionTokens
+		ap.TokenUsage.CachedTokens += cachedTokens
+		tokenUsage = ap.TokenUsage
+	})
+
+	state.activePlan.Stream(shared.StreamMessage{
+		Type:       shared.StreamMessageTokenUsage,
+		TokenUsage: &tokenUsage,
+	})
+
 	modelConfig := state.modelConfig
 	baseModelConfig := modelConfig.GetBaseModelConfig(state.authVars, state.settings, state.orgUserConfig)
 
@@ -64,6 +81,7 @@ func (state *activeTellStreamState) handleUsageChunk(usage *openai.Usage) {
 				ModelConfig:      state.modelConfig,
 
 				SessionId: sessionId,
+				TraceId:   state.activePlan.TraceId,
 			},
 		})
 
@@ -130,6 +148,7 @@ func (state *activeTellStreamState) execHookOnStop(sendStreamErr bool) {
 				ModelConfig:      state.modelConfig,
 
 				SessionId: active.SessionId,
+				TraceId:   active.TraceId,
 			},
 		})
 
@@ -45,6 +45,10 @@ func (state *activeTellStreamState) formatSubtasks() string {
 		subtasksText += "\n"
 	}
 
+	if state.maxSubtasks > 0 && len(state.subtasks) >= state.maxSubtasks && state.currentStage.TellStage == shared.TellStagePlanning && state.currentStage.PlanningPhase == shared.PlanningPhaseTasks {
+		subtasksText += fmt.Sprintf("\nThe plan has reached the maximum of %d subtasks. Any further subtasks you add will be dropped, so prioritize the most important remaining work and remove subtasks that aren't essential before adding new ones.\n", state.maxSubtasks)
+	}
+
 	if current != nil && state.currentStage.TellStage == shared.TellStageImplementation {
 		subtasksText += fmt.Sprintf("\n### Current subtask\n%s\n", current.Title)
 		if current.Description != "" {
@@ -80,6 +84,7 @@ func (state *activeTellStreamState) formatSubtasks() string {
 type checkNewSubtasksResult struct {
 	hasExplicitTasks bool
 	newSubtasks      []*db.Subtask
+	droppedSubtasks  []*db.Subtask
 }
 
 func (state *activeTellStreamState) checkNewSubtasks() checkNewSubtasksResult {
@@ -134,6 +139,13 @@ func (state *activeTellStreamState) checkNewSubtasks() checkNewSubtasksResult {
 		}
 	}
 
+	var droppedSubtasks []*db.Subtask
+	newSubtasks, droppedSubtasks = capNewSubtasks(len(updatedSubtasks)-len(newSubtasks), newSubtasks, state.maxSubtasks)
+	if len(droppedSubtasks) > 0 {
+		log.Printf("Max subtasks (%d) reached - dropped %d new subtasks\n", state.maxSubtasks, len(droppedSubtasks))
+		updatedSubtasks = updatedSubtasks[:len(updatedSubtasks)-len(droppedSubtasks)]
+	}
+
 	state.subtasks = updatedSubtasks
 
 	var currentSubtaskName string
@@ -168,7 +180,22 @@ func (state *activeTellStreamState) checkNewSubtasks() checkNewSubtasksResult {
 	return checkNewSubtasksResult{
 		hasExplicitTasks: len(subtasks) > 0,
 		newSubtasks:      newSubtasks,
+		droppedSubtasks:  droppedSubtasks,
+	}
+}
+
+// capNewSubtasks keeps as many of the proposed subtasks as fit under max alongside the existing ones, in order, and returns the rest as dropped
+func capNewSubtasks(numExisting int, proposed []*db.Subtask, max int) (kept, dropped []*db.Subtask) {
+	if numExisting+len(proposed) <= max {
+		return proposed, nil
 	}
+
+	room := max - numExisting
+	if room < 0 {
+		room = 0
+	}
+
+	return proposed[:room], proposed[room:]
 }
 
 type checkRemoveSubtasksResult struct {
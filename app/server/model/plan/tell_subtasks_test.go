@@ -0,0 +1,53 @@
+package plan
+
+import (
+	"fmt"
+	"plandex-server/db"
+	"testing"
+)
+
+func TestCapNewSubtasks(t *testing.T) {
+	makeSubtasks := func(n int) []*db.Subtask {
+		var subtasks []*db.Subtask
+		for i := 0; i < n; i++ {
+			subtasks = append(subtasks, &db.Subtask{Title: fmt.Sprintf("Task %d", i+1)})
+		}
+		return subtasks
+	}
+
+	tests := []struct {
+		name        string
+		numExisting int
+		numProposed int
+		max         int
+		wantKept    int
+		wantDropped int
+	}{
+		{name: "under the cap", numExisting: 2, numProposed: 3, max: 10, wantKept: 3, wantDropped: 0},
+		{name: "exactly at the cap", numExisting: 5, numProposed: 5, max: 10, wantKept: 5, wantDropped: 0},
+		{name: "excessive proposal is truncated", numExisting: 0, numProposed: 500, max: 100, wantKept: 100, wantDropped: 400},
+		{name: "existing subtasks count toward the cap", numExisting: 95, numProposed: 20, max: 100, wantKept: 5, wantDropped: 15},
+		{name: "already over the cap", numExisting: 120, numProposed: 3, max: 100, wantKept: 0, wantDropped: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed := makeSubtasks(tt.numProposed)
+			kept, dropped := capNewSubtasks(tt.numExisting, proposed, tt.max)
+
+			if len(kept) != tt.wantKept {
+				t.Errorf("kept %d subtasks, want %d", len(kept), tt.wantKept)
+			}
+			if len(dropped) != tt.wantDropped {
+				t.Errorf("dropped %d subtasks, want %d", len(dropped), tt.wantDropped)
+			}
+
+			// proposal order is preserved so the model's highest priority tasks are the ones kept
+			for i, subtask := range kept {
+				if subtask != proposed[i] {
+					t.Errorf("kept[%d] = %q, want %q", i, subtask.Title, proposed[i].Title)
+				}
+			}
+		})
+	}
+}
@@ -57,6 +57,11 @@ func (state *activeTellStreamState) addConversationMessages() bool {
 	log.Printf("Total tokens: %d\n", tokensBeforeConvo+conversationTokens)
 	log.Printf("Max tokens: %d\n", state.settings.GetPlannerEffectiveMaxTokens())
 
+	var latestSummary *db.ConvoSummary
+	if len(summaries) > 0 {
+		latestSummary = summaries[len(summaries)-1]
+	}
+
 	var summary *db.ConvoSummary
 	if (tokensBeforeConvo+conversationTokens) > state.settings.GetPlannerEffectiveMaxTokens() ||
 		conversationTokens > state.settings.GetPlannerMaxConvoTokens() {
@@ -123,22 +128,55 @@ func (state *activeTellStreamState) addConversationMessages() bool {
 		}
 
 		if summary == nil && tokensBeforeConvo+conversationTokens > state.settings.GetPlannerEffectiveMaxTokens() {
-			err := errors.New("couldn't get under token limit with conversation summary")
-			log.Printf("Error: %v\n", err)
-			go notify.NotifyErr(notify.SeverityInfo, fmt.Errorf("couldn't get under token limit with conversation summary"))
+			log.Println("Couldn't get under token limit with conversation summary. Dropping oldest conversation messages.")
 
-			active.StreamDoneCh <- &shared.ApiError{
-				Type:   shared.ApiErrorTypeOther,
-				Status: http.StatusInternalServerError,
-				Msg:    "Couldn't get under token limit with conversation summary",
+			budget := min(state.settings.GetPlannerMaxConvoTokens(), state.settings.GetPlannerEffectiveMaxTokens()-tokensBeforeConvo)
+
+			// the prompt message is added later in tell_exec.go and is already counted in tokensBeforeConvo, so it's never dropped
+			var candidates []*db.ConvoMessage
+			for _, convoMessage := range convo {
+				if state.promptConvoMessage != nil && convoMessage.Id == state.promptConvoMessage.Id {
+					continue
+				}
+				candidates = append(candidates, convoMessage)
 			}
-			return false
-		}
-	}
 
-	var latestSummary *db.ConvoSummary
-	if len(summaries) > 0 {
-		latestSummary = summaries[len(summaries)-1]
+			var kept []*db.ConvoMessage
+			var keptTokens int
+
+			// prefer keeping the latest summary so the model still knows the earlier state of the plan, then as many recent messages as fit
+			if latestSummary != nil && latestSummary.Tokens <= budget {
+				var afterSummary []*db.ConvoMessage
+				for _, convoMessage := range candidates {
+					if convoMessage.CreatedAt.After(latestSummary.LatestConvoMessageCreatedAt) {
+						afterSummary = append(afterSummary, convoMessage)
+					}
+				}
+				kept, keptTokens = trimConvoToTokenBudget(afterSummary, budget-latestSummary.Tokens)
+				summary = latestSummary
+				conversationTokens = keptTokens + latestSummary.Tokens
+			} else {
+				kept, keptTokens = trimConvoToTokenBudget(candidates, budget)
+				conversationTokens = keptTokens
+			}
+
+			log.Printf("Dropped %d of %d conversation messages | kept summary: %t | conversation tokens: %d\n", len(candidates)-len(kept), len(candidates), summary != nil, conversationTokens)
+
+			convo = kept
+
+			if tokensBeforeConvo+conversationTokens > state.settings.GetPlannerEffectiveMaxTokens() {
+				err := errors.New("couldn't get under token limit even after dropping conversation history")
+				log.Printf("Error: %v\n", err)
+				go notify.NotifyErr(notify.SeverityInfo, err)
+
+				active.StreamDoneCh <- &shared.ApiError{
+					Type:   shared.ApiErrorTypeOther,
+					Status: http.StatusInternalServerError,
+					Msg:    "Couldn't get under token limit even after dropping conversation history",
+				}
+				return false
+			}
+		}
 	}
 
 	if summary == nil {
@@ -230,6 +268,21 @@ func (state *activeTellStreamState) addConversationMessages() bool {
 	return true
 }
 
+// trimConvoToTokenBudget keeps the most recent contiguous run of messages that fits within budget, dropping the oldest first
+func trimConvoToTokenBudget(convo []*db.ConvoMessage, budget int) ([]*db.ConvoMessage, int) {
+	tokens := 0
+	start := len(convo)
+	for i := len(convo) - 1; i >= 0; i-- {
+		msgTokens := convo[i].Tokens + model.TokensPerMessage + model.TokensPerName
+		if tokens+msgTokens > budget {
+			break
+		}
+		tokens += msgTokens
+		start = i
+	}
+	return convo[start:], tokens
+}
+
 type summarizeConvoParams struct {
 	auth                  *types.ServerAuth
 	plan                  *db.Plan
@@ -0,0 +1,78 @@
+package plan
+
+import (
+	"fmt"
+	"plandex-server/db"
+	"plandex-server/model"
+	"testing"
+)
+
+func TestTrimConvoToTokenBudget(t *testing.T) {
+	perMessageOverhead := model.TokensPerMessage + model.TokensPerName
+
+	// an artificially long history: 500 messages of 1000 tokens each
+	var convo []*db.ConvoMessage
+	for i := 0; i < 500; i++ {
+		convo = append(convo, &db.ConvoMessage{
+			Id:     fmt.Sprintf("msg-%d", i),
+			Tokens: 1000,
+		})
+	}
+
+	tests := []struct {
+		name       string
+		budget     int
+		wantKept   int
+		wantTokens int
+	}{
+		{
+			name:       "keeps most recent messages that fit",
+			budget:     10 * (1000 + perMessageOverhead),
+			wantKept:   10,
+			wantTokens: 10 * (1000 + perMessageOverhead),
+		},
+		{
+			name:       "partial message doesn't fit",
+			budget:     10*(1000+perMessageOverhead) + 999,
+			wantKept:   10,
+			wantTokens: 10 * (1000 + perMessageOverhead),
+		},
+		{
+			name:       "whole history fits",
+			budget:     1_000_000,
+			wantKept:   500,
+			wantTokens: 500 * (1000 + perMessageOverhead),
+		},
+		{
+			name:       "no room for any message",
+			budget:     500,
+			wantKept:   0,
+			wantTokens: 0,
+		},
+		{
+			name:       "negative budget",
+			budget:     -1,
+			wantKept:   0,
+			wantTokens: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, tokens := trimConvoToTokenBudget(convo, tt.budget)
+
+			if len(kept) != tt.wantKept {
+				t.Fatalf("kept %d messages, want %d", len(kept), tt.wantKept)
+			}
+			if tokens != tt.wantTokens {
+				t.Errorf("tokens = %d, want %d", tokens, tt.wantTokens)
+			}
+			if tokens > tt.budget && tt.wantKept > 0 {
+				t.Errorf("tokens %d exceed budget %d", tokens, tt.budget)
+			}
+			if len(kept) > 0 && kept[len(kept)-1].Id != "msg-499" {
+				t.Errorf("expected most recent message to be kept, got %s", kept[len(kept)-1].Id)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"context"
+	"math/rand"
+	"plandex-server/utils"
+	"time"
+
+	shared "plandex-shared"
+)
+
+var retryBaseDelay = time.Duration(utils.GetEnvIntMin("PLANDEX_MODEL_RETRY_BASE_DELAY_MS", 1000, 1)) * time.Millisecond
+
+// backoff is capped at the same delay above which a provider's retry after is treated as non-retriable
+const retryMaxDelay = time.Duration(MAX_RETRY_DELAY_SECONDS) * time.Second
+
+// GetRetryDelay returns how long to wait before retrying a failed model request, given the number of retries already made
+func GetRetryDelay(numRetry int, modelErr *shared.ModelError) time.Duration {
+	return getRetryDelay(numRetry, modelErr, retryBaseDelay, retryMaxDelay, rand.Float64())
+}
+
+func getRetryDelay(numRetry int, modelErr *shared.ModelError, baseDelay, maxDelay time.Duration, jitter float64) time.Duration {
+	if modelErr != nil && modelErr.RetryAfterSeconds > 0 {
+		// if the model err has a retry after, then use that with a bit of padding
+		// retry afters above MAX_RETRY_DELAY_SECONDS are already treated as non-retriable
+		return time.Duration(int(float64(modelErr.RetryAfterSeconds)*1.1)) * time.Second
+	}
+
+	// otherwise, back off exponentially from the base delay
+	delay := baseDelay
+	for i := 0; i < numRetry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	// add up to 20% jitter so that concurrent requests don't retry in lockstep
+	delay += time.Duration(jitter * 0.2 * float64(delay))
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// SleepForRetry waits for the given delay, returning false early if ctx is cancelled first
+func SleepForRetry(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	shared "plandex-shared"
+)
+
+func TestGetRetryDelay(t *testing.T) {
+	base := time.Second
+	max := 30 * time.Second
+
+	tests := []struct {
+		name     string
+		numRetry int
+		modelErr *shared.ModelError
+		jitter   float64
+		want     time.Duration
+	}{
+		{
+			name:     "first retry uses base delay",
+			numRetry: 0,
+			want:     time.Second,
+		},
+		{
+			name:     "doubles on each retry",
+			numRetry: 3,
+			want:     8 * time.Second,
+		},
+		{
+			name:     "adds jitter",
+			numRetry: 1,
+			jitter:   0.5,
+			want:     2*time.Second + 200*time.Millisecond,
+		},
+		{
+			name:     "capped at max delay",
+			numRetry: 10,
+			want:     max,
+		},
+		{
+			name:     "jitter doesn't push past max delay",
+			numRetry: 10,
+			jitter:   0.99,
+			want:     max,
+		},
+		{
+			name:     "retry after takes precedence",
+			numRetry: 3,
+			modelErr: &shared.ModelError{RetryAfterSeconds: 5},
+			jitter:   0.5,
+			want:     5 * time.Second,
+		},
+		{
+			name:     "model error without retry after backs off",
+			numRetry: 2,
+			modelErr: &shared.ModelError{Kind: shared.ErrRateLimited},
+			want:     4 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getRetryDelay(tt.numRetry, tt.modelErr, base, max, tt.jitter)
+			if got != tt.want {
+				t.Errorf("getRetryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSleepForRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if SleepForRetry(ctx, time.Minute) {
+		t.Error("expected SleepForRetry to return false for a cancelled context")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("SleepForRetry waited %v after cancellation", time.Since(start))
+	}
+
+	if !SleepForRetry(context.Background(), time.Millisecond) {
+		t.Error("expected SleepForRetry to return true once the delay elapses")
+	}
+}
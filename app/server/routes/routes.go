@@ -194,6 +194,7 @@ func addProxyableApiRoutes(r *mux.Router, prefix string) {
 
 	HandlePlandexFn(r, prefix+"/plans/{planId}/{branch}/connect", true, handlers.ConnectPlanHandler).Methods("PATCH")
 	HandlePlandexFn(r, prefix+"/plans/{planId}/{branch}/stop", false, handlers.StopPlanHandler).Methods("DELETE")
+	HandlePlandexFn(r, prefix+"/plans/{planId}/{branch}/detach", false, handlers.DetachPlanHandler).Methods("PATCH")
 
 	HandlePlandexFn(r, prefix+"/plans/{planId}/{branch}/respond_missing_file", false, handlers.RespondMissingFileHandler).Methods("POST")
 
@@ -76,12 +76,20 @@ type ActivePlan struct {
 	MissingFilePath       string
 	MissingFileResponseCh chan shared.RespondMissingFileChoice
 	AutoContext           bool
-	AutoLoadContextCh     chan struct{}
+	AutoLoadContextCh     chan string
+	AutoLoadContextId     string
 	AllowOverwritePaths   map[string]bool
 	SkippedPaths          map[string]bool
+	AutoLoadedPaths       map[string]bool
+	RemovedContextIds     map[string]bool
 	StoredReplyIds        []string
 	DidEditFiles          bool
 	SessionId             string
+	TokenUsage            shared.TokenUsage
+	Detached              bool
+
+	// TraceId identifies a single Tell across all its iterations so it can be traced through logs, hooks, and errors -- distinct from the provider's per-request generation id
+	TraceId string
 
 	subscriptions  map[string]*subscription
 	subscriptionMu sync.Mutex
@@ -122,10 +130,13 @@ func NewActivePlan(orgId, userId, planId, branch, prompt string, buildOnly, auto
 		StreamDoneCh:          make(chan *shared.ApiError),
 		MissingFileResponseCh: make(chan shared.RespondMissingFileChoice),
 		AutoContext:           autoContext,
-		AutoLoadContextCh:     make(chan struct{}),
+		AutoLoadContextCh:     make(chan string, 1),
 		AllowOverwritePaths:   map[string]bool{},
 		SkippedPaths:          map[string]bool{},
+		AutoLoadedPaths:       map[string]bool{},
+		RemovedContextIds:     map[string]bool{},
 		SessionId:             sessionId,
+		TraceId:               uuid.New().String(),
 		streamCh:              make(chan string),
 		subscriptions:         map[string]*subscription{},
 		subscriptionMu:        sync.Mutex{},
@@ -143,9 +154,12 @@ func NewActivePlan(orgId, userId, planId, branch, prompt string, buildOnly, auto
 			case <-active.Ctx.Done():
 				return
 			case msg := <-active.streamCh:
-				var subscriptions map[string]*subscription
+				// copy the subscriptions so they can be sent to without holding the lock
 				active.subscriptionMu.Lock()
-				subscriptions = active.subscriptions
+				subscriptions := make([]*subscription, 0, len(active.subscriptions))
+				for _, sub := range active.subscriptions {
+					subscriptions = append(subscriptions, sub)
+				}
 				active.subscriptionMu.Unlock()
 				for _, sub := range subscriptions {
 					sub.enqueueMessage(msg)
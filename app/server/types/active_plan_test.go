@@ -0,0 +1,27 @@
+package types
+
+import (
+	"context"
+	"plandex-server/shutdown"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestActivePlanTraceId(t *testing.T) {
+	shutdown.ShutdownCtx, shutdown.ShutdownCancel = context.WithCancel(context.Background())
+	defer shutdown.ShutdownCancel()
+
+	a := NewActivePlan("org", "user", "plan", "main", "prompt", false, false, "session")
+	b := NewActivePlan("org", "user", "plan", "main", "prompt", false, false, "session")
+	defer a.CancelFn()
+	defer b.CancelFn()
+
+	if _, err := uuid.Parse(a.TraceId); err != nil {
+		t.Fatalf("TraceId %q is not a valid uuid: %v", a.TraceId, err)
+	}
+
+	if a.TraceId == b.TraceId {
+		t.Errorf("expected distinct trace ids for separate active plans, got %q twice", a.TraceId)
+	}
+}
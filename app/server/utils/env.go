@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// GetEnvInt returns the integer value of the named env var, or def if it's unset or invalid
+func GetEnvInt(name string, def int) int {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q - using default %d\n", name, s, def)
+		return def
+	}
+
+	return n
+}
+
+// GetEnvIntMin is like GetEnvInt, but also falls back to def if the value is below min
+func GetEnvIntMin(name string, def, min int) int {
+	n := GetEnvInt(name, def)
+	if n < min {
+		log.Printf("Invalid value for %s: %d is below the minimum of %d - using default %d\n", name, n, min, def)
+		return def
+	}
+
+	return n
+}
+
+// GetEnvBool returns the boolean value of the named env var, or def if it's unset or invalid
+func GetEnvBool(name string, def bool) bool {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q - using default %t\n", name, s, def)
+		return def
+	}
+
+	return b
+}
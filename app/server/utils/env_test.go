@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestGetEnvIntMin(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "unset uses default", value: "", want: 30},
+		{name: "valid value", value: "5", want: 5},
+		{name: "minimum is allowed", value: "1", want: 1},
+		{name: "below minimum uses default", value: "0", want: 30},
+		{name: "negative uses default", value: "-3", want: 30},
+		{name: "not a number uses default", value: "soon", want: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PLANDEX_TEST_ENV_INT", tt.value)
+
+			if got := GetEnvIntMin("PLANDEX_TEST_ENV_INT", 30, 1); got != tt.want {
+				t.Errorf("GetEnvIntMin() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -51,6 +51,9 @@ type ApiError struct {
 	Status int          `json:"status"`
 	Msg    string       `json:"msg"`
 
+	// set for errors from an active plan stream so a failed generation can be referenced in bug reports
+	TraceId string `json:"traceId,omitempty"`
+
 	// only used for trial plans exceeded error
 	TrialPlansExceededError *TrialPlansExceededError `json:"trialPlansExceededError,omitempty"`
 
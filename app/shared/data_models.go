@@ -173,6 +173,7 @@ type ConvoMessage struct {
 	AddedSubtasks    []*Subtask        `json:"addedSubtasks,omitempty"`
 	RemovedSubtasks  []string          `json:"removedSubtasks,omitempty"`
 	ActiveContextIds []string          `json:"activeContextIds"`
+	ModelFallback    *ModelFallback    `json:"modelFallback,omitempty"`
 	CreatedAt        time.Time         `json:"createdAt"`
 }
 
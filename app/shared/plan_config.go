@@ -9,6 +9,8 @@ import (
 
 const defaultAutoDebugTries = 5
 
+const defaultMaxSubtasks = 100
+
 const (
 	EditorTypeVim  string = "vim"
 	EditorTypeNano string = "nano"
@@ -66,6 +68,9 @@ type PlanConfig struct {
 	AutoLoadContext   bool `json:"autoContext"`
 	SmartContext      bool `json:"smartContext"`
 
+	// by default, files the client was already asked to auto load during a session aren't requested again unless they're removed from context
+	AutoLoadRepeatPaths bool `json:"autoLoadRepeatPaths"`
+
 	// AutoApproveContext bool `json:"autoApproveContext"`
 	// QuietContext       bool `json:"quietContext"`
 
@@ -85,8 +90,14 @@ type PlanConfig struct {
 
 	AutoRevertOnRewind bool `json:"autoRevertOnRewind"`
 
+	// by default, a reply that's stopped mid-stream is stored in the conversation so it can be inspected or continued from
+	DiscardPartialReplyOnStop bool `json:"discardPartialReplyOnStop"`
+
 	SkipChangesMenu bool `json:"skipChangesMenu"`
 
+	// upper bound on the number of subtasks in a plan, to guard against runaway planning -- 0 uses the default
+	MaxSubtasks int `json:"maxSubtasks"`
+
 	// ReplMode    bool     `json:"replMode"`
 	// DefaultRepl ReplType `json:"defaultRepl"`
 
@@ -125,6 +136,13 @@ func (p PlanConfig) Value() (driver.Value, error) {
 	return json.Marshal(p)
 }
 
+func (p PlanConfig) GetMaxSubtasks() int {
+	if p.MaxSubtasks <= 0 {
+		return defaultMaxSubtasks
+	}
+	return p.MaxSubtasks
+}
+
 func (p *PlanConfig) SetAutoMode(mode AutoModeType) {
 	p.AutoMode = mode
 
@@ -321,6 +339,19 @@ var ConfigSettingsByKey = map[string]ConfigSetting{
 			return fmt.Sprintf("%t", p.AutoLoadContext)
 		},
 	},
+	"autoloadrepeatpaths": {
+		Name: "auto-load-repeat-paths",
+		Desc: "Request files for auto-loading again even if they were already requested earlier in the session",
+		Visible: func(p *PlanConfig) bool {
+			return p.AutoLoadContext
+		},
+		BoolSetter: func(p *PlanConfig, enabled bool) {
+			p.AutoLoadRepeatPaths = enabled
+		},
+		Getter: func(p *PlanConfig) string {
+			return fmt.Sprintf("%t", p.AutoLoadRepeatPaths)
+		},
+	},
 	"smartcontext": {
 		Name: "smart-context",
 		Desc: "Load only necessary context for each task in the plan",
@@ -476,6 +507,16 @@ var ConfigSettingsByKey = map[string]ConfigSetting{
 			return fmt.Sprintf("%t", p.AutoRevertOnRewind)
 		},
 	},
+	"discardpartialreply": {
+		Name: "discard-partial-reply",
+		Desc: "Discard a reply that's stopped mid-stream instead of storing it in the conversation",
+		BoolSetter: func(p *PlanConfig, enabled bool) {
+			p.DiscardPartialReplyOnStop = enabled
+		},
+		Getter: func(p *PlanConfig) string {
+			return fmt.Sprintf("%t", p.DiscardPartialReplyOnStop)
+		},
+	},
 	"skipchangesmenu": {
 		Name: "skip-changes-menu",
 		Desc: "Skip interactive menu when response finishes and changes are pending",
@@ -486,6 +527,16 @@ var ConfigSettingsByKey = map[string]ConfigSetting{
 			return fmt.Sprintf("%t", p.SkipChangesMenu)
 		},
 	},
+	"maxsubtasks": {
+		Name: "max-subtasks",
+		Desc: "Maximum number of subtasks in a plan",
+		IntSetter: func(p *PlanConfig, value int) {
+			p.MaxSubtasks = value
+		},
+		Getter: func(p *PlanConfig) string {
+			return fmt.Sprintf("%d", p.GetMaxSubtasks())
+		},
+	},
 }
 
 func init() {
@@ -140,6 +140,9 @@ type TellPlanRequest struct {
 	IsImplementationOfChat bool            `json:"isImplementationOfChat"`
 	IsGitRepo              bool            `json:"isGitRepo"`
 	SessionId              string          `json:"sessionId"`
+
+	// stop the plan if the client's stream disconnects without detaching and doesn't reconnect
+	StopOnDisconnect bool `json:"stopOnDisconnect"`
 }
 
 type BuildPlanRequest struct {
@@ -211,6 +214,9 @@ type LoadContextParams struct {
 
 type LoadContextRequest []*LoadContextParams
 
+// LoadContextIdHeader echoes the LoadContextId of the load context stream message an auto load context request responds to
+const LoadContextIdHeader = "X-Load-Context-Id"
+
 type LoadContextResponse struct {
 	TokensAdded       int    `json:"tokensAdded"`
 	TotalTokens       int    `json:"totalTokens"`
@@ -9,6 +9,20 @@ type BuildInfo struct {
 	Removed   bool   `json:"removed,omitempty"`
 }
 
+// TokenUsage is the cumulative token usage reported by the model provider across all model requests for a Tell
+type TokenUsage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	CachedTokens     int `json:"cachedTokens"`
+}
+
+// ModelFallback describes a request being routed to a different model than the one configured for its role
+type ModelFallback struct {
+	FromModel string       `json:"fromModel"`
+	ToModel   string       `json:"toModel"`
+	Reason    FallbackType `json:"reason"`
+}
+
 type StreamMessageType string
 
 const (
@@ -24,6 +38,8 @@ const (
 	StreamMessageAborted           StreamMessageType = "aborted"
 	StreamMessageFinished          StreamMessageType = "finished"
 	StreamMessageError             StreamMessageType = "error"
+	StreamMessageTokenUsage        StreamMessageType = "tokenUsage"
+	StreamMessageModelFallback     StreamMessageType = "modelFallback"
 
 	StreamMessageMulti StreamMessageType = "multi"
 )
@@ -40,9 +56,12 @@ type StreamMessage struct {
 	MissingFileAutoContext bool                     `json:"missingFileAutoContext,omitempty"`
 	ModelStreamId          string                   `json:"modelStreamId,omitempty"`
 	LoadContextFiles       []string                 `json:"loadContextFiles,omitempty"`
+	LoadContextId          string                   `json:"loadContextId,omitempty"`
 	InitPrompt             string                   `json:"initPrompt,omitempty"`
 	InitReplies            []string                 `json:"initReplies,omitempty"`
 	InitBuildOnly          bool                     `json:"initBuildOnly,omitempty"`
+	TokenUsage             *TokenUsage              `json:"tokenUsage,omitempty"`
+	ModelFallback          *ModelFallback           `json:"modelFallback,omitempty"`
 
 	StreamMessages []StreamMessage `json:"streamMessages,omitempty"`
 }